@@ -0,0 +1,829 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"gymtimer/internal/benchmark"
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+	"gymtimer/internal/export"
+	"gymtimer/internal/headless"
+	"gymtimer/internal/heat"
+	"gymtimer/internal/history"
+	"gymtimer/internal/leaderboard"
+	"gymtimer/internal/schedule"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+	"gymtimer/internal/ui"
+	"gymtimer/internal/workout"
+)
+
+const usage = `Usage: gymtimer [global flags] [command] [flags]
+
+Commands:
+  clock   [--schedule FILE]  Show the clock (default), arming each class
+                             of the day plan (JSON or .ics) in turn
+  tabata  [--work 20s] [--rest 10s] [--rounds 8] [--sets 1] [--set-rest 1m]
+  emom    [--every 1m] [--rounds 10] [--athletes "Sam,Alex"]
+          [--rotate "Swings,Burpees"]
+  amrap   [DURATION]         e.g. "gymtimer amrap 20m"
+  custom  [--work 30s] [--rest 15s] [--rounds 5] [--athletes "Sam,Alex"]
+          [--sets 1] [--set-rest 1m] [--ladder 10s | --pyramid 10s]
+          [--rest-step 5s]
+  circuit [--stations "Bike,Rower,Wall balls"] [--work 45s] [--move 15s]
+          [--laps 1]         Rotate groups through the stations, one group
+                             starting at each
+  deathby [--reps 1] [--step 1] [--minutes 30]
+                             Add reps every minute until failure; press
+                             Enter on the minute you fail
+  chaos   [DURATION] [--work 15s] [--work-max 45s] [--rest 10s]
+          [--rest-max 30s] [--seed N]
+                             Random work and rest within the ranges for
+                             the duration (20m); the same seed repeats
+                             the session
+  stopwatch
+  fortime [--cap 10m]        Count up until you press Enter, with an
+                             optional time cap
+  run     [--workout] FILE   Run a workout file
+  benchmark [NAME]           List the benchmark workouts with your best
+                             results, or run one, e.g. "benchmark fran"
+  leaderboard [--file FILE] [ACTION]
+                             Keep in-house competition results. Actions:
+                             print (default), show (full screen),
+                             workout NAME [--scoring time|reps|rounds+reps|load],
+                             score WORKOUT ATHLETE RESULT ("3:45", "120 reps"),
+                             points 100,95,...|places, name TEXT
+  export  [--format fit|tcx|csv|json] [--since YYYY-MM-DD] [--out PATH]
+          [--intervals]      Write finished sessions: fit and tcx as one
+                             file per session (by default only the latest)
+                             into a directory, csv and json as one table
+                             (by default all history) to a file or stdout
+
+With --athletes, emom and custom rounds rotate through the athletes ("you
+go, I go"), showing who works now and next and each athlete's work time.
+With --rotate, emom rounds cycle through the movements, e.g. odd minutes
+swings and even minutes burpees.
+
+With --sets, tabata and custom repeat their rounds, resting --set-rest
+between sets; --rounds counts the rounds in each set.
+
+custom --ladder adds the step to the work every round (30/40/50/60s), and
+--pyramid climbs to the middle round and back down (30/40/50/40/30s).
+
+chaos picks a new seed each run and shows it with the workout, so a good
+session can be run again with --seed. The next interval stays hidden from
+the athletes; the coach can press C to see it.
+
+amrap and fortime accept --lanes N and --athletes "Sam,Alex,..." to time
+a heat: each lane's number key captures its finish, and the results can
+be ranked and exported from the finished screen.
+
+Workout commands start the timer immediately, or with --at HH:MM[:SS]
+at the next occurrence of that time. They also accept --headless to run
+without the terminal UI and --json to print events as JSON lines.
+
+Global flags:
+  --config FILE    config file (default ` + "%s" + `)
+  --assets DIR     directory holding beep.wav and chime.wav
+  --theme NAME     color theme: default, high-contrast, light
+  --sound on|off   enable or disable sounds
+`
+
+// options holds the flags shared by every command
+type options struct {
+	configPath string
+	assetsDir  string
+	theme      string
+	sound      string
+
+	// Only registered by the clock command
+	schedule string
+
+	// Only registered by workout commands
+	headless bool
+	json     bool
+	at       string
+
+	// Set when the workout is a scored benchmark
+	benchmark *benchmark.Benchmark
+
+	// Set when timing a heat of athletes
+	heat *heat.Heat
+
+	// Set by leaderboard commands
+	leaderboard string
+	showBoard   bool
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.configPath, "config", o.configPath, "config file")
+	fs.StringVar(&o.assetsDir, "assets", o.assetsDir, "directory holding beep.wav and chime.wav")
+	fs.StringVar(&o.theme, "theme", o.theme, "color theme")
+	fs.StringVar(&o.sound, "sound", o.sound, "enable sounds (on|off)")
+}
+
+func (o *options) registerRun(fs *flag.FlagSet) {
+	fs.BoolVar(&o.headless, "headless", false, "run without the terminal UI, printing one line per event")
+	fs.BoolVar(&o.json, "json", false, "with --headless, print events as JSON lines")
+	fs.StringVar(&o.at, "at", "", "start at this wall-clock time (HH:MM or HH:MM:SS)")
+}
+
+// runCLI parses the command line and runs the selected command, returning
+// the process exit code
+func runCLI(args []string) int {
+	o := &options{configPath: config.DefaultPath()}
+
+	fs := newFlagSet("gymtimer", o)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	args = fs.Args()
+
+	command := "clock"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "clock":
+		return clockCommand(o, args)
+	case "tabata", "emom", "amrap", "custom", "stopwatch", "fortime", "circuit", "deathby", "chaos":
+		return modeCommand(command, o, args)
+	case "run":
+		return runCommand(o, args)
+	case "benchmark":
+		return benchmarkCommand(o, args)
+	case "export":
+		return exportCommand(o, args)
+	case "leaderboard":
+		return leaderboardCommand(o, args)
+	case "help":
+		printUsage(os.Stdout)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", command)
+		printUsage(os.Stderr)
+		return 2
+	}
+}
+
+func newFlagSet(name string, o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { printUsage(fs.Output()) }
+	o.register(fs)
+	return fs
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, usage, config.DefaultPath())
+}
+
+// parseArgs parses flags that may appear before or after positional
+// arguments and returns the positional ones
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func clockCommand(o *options, args []string) int {
+	fs := newFlagSet("clock", o)
+	fs.StringVar(&o.schedule, "schedule", "", "day plan of classes to arm in turn (JSON or .ics)")
+	if positional, err := parseArgs(fs, args); err != nil {
+		return 2
+	} else if len(positional) > 0 {
+		fs.Usage()
+		return 2
+	}
+	return o.start(nil)
+}
+
+// modeCommand starts a workout in the named mode, configured from flags
+func modeCommand(name string, o *options, args []string) int {
+	mode, _ := timer.ParseMode(name)
+	t := timer.New()
+	t.SetMode(mode)
+
+	fs := newFlagSet(name, o)
+	o.registerRun(fs)
+	switch mode {
+	case timer.ModeTabata, timer.ModeCustom:
+		fs.DurationVar(&t.WorkDuration, "work", t.WorkDuration, "work interval")
+		fs.DurationVar(&t.RestDuration, "rest", t.RestDuration, "rest interval")
+		fs.IntVar(&t.TotalRounds, "rounds", t.TotalRounds, "number of rounds (in each set)")
+		fs.DurationVar(&t.SetRest, "set-rest", t.SetRest, "rest between sets")
+	case timer.ModeEMOM:
+		fs.DurationVar(&t.WorkDuration, "every", t.WorkDuration, "length of each round")
+		fs.IntVar(&t.TotalRounds, "rounds", t.TotalRounds, "number of rounds")
+	case timer.ModeDeathBy:
+		fs.IntVar(&t.StartReps, "reps", t.StartReps, "reps in the first minute")
+		fs.IntVar(&t.RepStep, "step", t.RepStep, "reps added every minute")
+		fs.IntVar(&t.TotalRounds, "minutes", t.TotalRounds, "most minutes to run")
+	case timer.ModeAMRAP:
+		fs.DurationVar(&t.Duration, "duration", t.Duration, "time cap (may also be given as an argument)")
+	case timer.ModeChaos:
+		fs.DurationVar(&t.Duration, "duration", t.Duration, "length of the session (may also be given as an argument)")
+		fs.DurationVar(&t.WorkDuration, "work", t.WorkDuration, "shortest work interval")
+		fs.DurationVar(&t.WorkMax, "work-max", t.WorkMax, "longest work interval")
+		fs.DurationVar(&t.RestDuration, "rest", t.RestDuration, "shortest rest interval")
+		fs.DurationVar(&t.RestMax, "rest-max", t.RestMax, "longest rest interval")
+		fs.Int64Var(&t.Seed, "seed", t.Seed, "seed for the random intervals; the same seed repeats a session")
+	case timer.ModeForTime:
+		fs.DurationVar(&t.Duration, "cap", t.Duration, "time cap (0 for none)")
+	case timer.ModeCircuit:
+		fs.DurationVar(&t.WorkDuration, "work", t.WorkDuration, "work at each station")
+		fs.DurationVar(&t.RestDuration, "move", t.RestDuration, "time to move to the next station")
+	}
+	var stations string
+	laps := 1
+	if mode == timer.ModeCircuit {
+		fs.StringVar(&stations, "stations", "", "comma-separated station names")
+		fs.IntVar(&laps, "laps", laps, "times round the stations")
+	}
+	sets := 1
+	if mode == timer.ModeTabata || mode == timer.ModeCustom {
+		fs.IntVar(&sets, "sets", sets, "repeat the rounds this many times")
+	}
+	var ladder, pyramid time.Duration
+	if mode == timer.ModeCustom {
+		fs.DurationVar(&ladder, "ladder", 0, "add this much work every round, e.g. 10s (negative counts down)")
+		fs.DurationVar(&pyramid, "pyramid", 0, "add this much work each round up to the middle, then take it off again")
+		fs.DurationVar(&t.RestStep, "rest-step", 0, "with --ladder or --pyramid, also change the rest by this much")
+	}
+	var rotate string
+	if mode == timer.ModeEMOM {
+		fs.StringVar(&rotate, "rotate", "", "comma-separated movements the rounds cycle through")
+	}
+	var lanes int
+	var athletes string
+	switch mode {
+	case timer.ModeAMRAP, timer.ModeForTime:
+		fs.IntVar(&lanes, "lanes", 0, "time a heat with this many lanes")
+		fs.StringVar(&athletes, "athletes", "", "comma-separated athlete names, one per lane")
+	case timer.ModeEMOM, timer.ModeCustom:
+		fs.StringVar(&athletes, "athletes", "", "comma-separated athlete names taking turns, one round each")
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if (mode == timer.ModeAMRAP || mode == timer.ModeChaos) && len(positional) == 1 {
+		d, err := parseMinutes(positional[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid duration %q\n", positional[0])
+			return 2
+		}
+		t.Duration = d
+		positional = nil
+	}
+	if len(positional) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n", positional[0])
+		return 2
+	}
+
+	if mode == timer.ModeCircuit {
+		if stations != "" {
+			t.Stations = splitNames(stations)
+		}
+		t.SetLaps(laps)
+		if laps < 1 || len(t.Stations) < 2 {
+			fmt.Fprintln(os.Stderr, "Error: a circuit needs at least 2 stations and 1 lap")
+			return 2
+		}
+	}
+
+	if sets < 1 || t.SetRest < 0 {
+		fmt.Fprintln(os.Stderr, "Error: sets and the rest between them must be positive")
+		return 2
+	}
+	t.SetSets(sets)
+
+	switch {
+	case ladder != 0 && pyramid != 0:
+		fmt.Fprintln(os.Stderr, "Error: choose either --ladder or --pyramid")
+		return 2
+	case ladder != 0:
+		t.Progression, t.WorkStep = timer.Ladder, ladder
+	case pyramid != 0:
+		t.Progression, t.WorkStep = timer.Pyramid, pyramid
+	case t.RestStep != 0:
+		fmt.Fprintln(os.Stderr, "Error: --rest-step needs --ladder or --pyramid")
+		return 2
+	}
+
+	if rotate != "" {
+		t.Movements = nil
+		for _, name := range splitNames(rotate) {
+			t.Movements = append(t.Movements, timer.Movement{Label: name})
+		}
+		t.Rotation = len(t.Movements)
+		if t.Rotation < 2 {
+			fmt.Fprintln(os.Stderr, "Error: a rotation needs at least 2 movements")
+			return 2
+		}
+	}
+
+	if (mode == timer.ModeEMOM || mode == timer.ModeCustom) && athletes != "" {
+		t.Athletes = splitNames(athletes)
+	} else if lanes > 0 || athletes != "" {
+		if o.heat, err = newHeat(lanes, athletes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	if mode == timer.ModeDeathBy && (t.StartReps < 1 || t.RepStep < 1) {
+		fmt.Fprintln(os.Stderr, "Error: reps must be positive")
+		return 2
+	}
+
+	if mode == timer.ModeChaos {
+		if t.Seed < 1 || t.WorkMax < t.WorkDuration || t.RestMax < t.RestDuration {
+			fmt.Fprintln(os.Stderr, "Error: the seed must be positive and each longest interval at least the shortest")
+			return 2
+		}
+		t.Draw()
+	}
+
+	capped := mode != timer.ModeForTime
+	if t.WorkDuration <= 0 || t.RestDuration < 0 || t.TotalRounds < 1 || t.Duration < 0 || (capped && t.Duration == 0) {
+		fmt.Fprintln(os.Stderr, "Error: durations and rounds must be positive")
+		return 2
+	}
+
+	return o.start(t)
+}
+
+// newHeat creates a heat from the --lanes and --athletes flags. Without
+// --lanes there is one lane per athlete.
+func newHeat(lanes int, athletes string) (*heat.Heat, error) {
+	names := splitNames(athletes)
+	if lanes == 0 {
+		lanes = len(names)
+	}
+	return heat.New(lanes, names)
+}
+
+// splitNames reads a comma-separated list of names
+func splitNames(list string) []string {
+	var names []string
+	if list != "" {
+		for _, name := range strings.Split(list, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return names
+}
+
+// parseMinutes reads a duration such as "20m" or a bare number of minutes
+func parseMinutes(s string) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return time.Duration(n) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, errors.New("invalid duration")
+	}
+	return d, nil
+}
+
+// parseStartTime returns the next occurrence of a wall-clock time such as
+// "18:00" or "18:00:30"
+func parseStartTime(s string, now time.Time) (time.Time, error) {
+	var clock time.Time
+	var err error
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if clock, err = time.Parse(layout, s); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q, expected HH:MM or HH:MM:SS", s)
+	}
+
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// runCommand implements `gymtimer run`, which executes a workout file
+func runCommand(o *options, args []string) int {
+	fs := newFlagSet("run", o)
+	o.registerRun(fs)
+	workoutPath := fs.String("workout", "", "workout file to run")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if *workoutPath == "" && len(positional) == 1 {
+		*workoutPath = positional[0]
+	}
+	if *workoutPath == "" {
+		fs.Usage()
+		return 2
+	}
+
+	w, err := workout.Load(*workoutPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	t, err := w.Timer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *workoutPath, err)
+		return 1
+	}
+	if b, ok, err := benchmark.FromWorkout(w); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *workoutPath, err)
+		return 1
+	} else if ok {
+		o.benchmark = &b
+	}
+	return o.start(t)
+}
+
+// benchmarkCommand implements `gymtimer benchmark`, which lists the
+// benchmark workouts or runs one by name
+func benchmarkCommand(o *options, args []string) int {
+	fs := newFlagSet("benchmark", o)
+	o.registerRun(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	lib, err := benchmark.Library(benchmark.Dir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if len(positional) == 0 {
+		sessions, err := history.Load(history.DefaultPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		for _, b := range lib {
+			t, err := b.Workout.Timer()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", b.Name(), err)
+				return 1
+			}
+			line := fmt.Sprintf("%-22s %-20s %-12s", b.Name(), t.Summary(), b.Scoring)
+			if best := history.Best(sessions, b.Name(), b.Scoring); best != nil {
+				line += " best " + best.String()
+			}
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+		return 0
+	}
+
+	name := strings.Join(positional, " ")
+	b, ok := benchmark.Find(lib, name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown benchmark %q\n", name)
+		return 1
+	}
+	t, err := b.Workout.Timer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", b.Name(), err)
+		return 1
+	}
+	o.benchmark = &b
+	return o.start(t)
+}
+
+// exportCommand implements `gymtimer export`. FIT and TCX write one file
+// per session into a directory; CSV and JSON write a single table.
+func exportCommand(o *options, args []string) int {
+	fs := newFlagSet("export", o)
+	format := fs.String("format", "fit", "fit, tcx, csv or json")
+	since := fs.String("since", "", "export every session since this date (YYYY-MM-DD)")
+	out := fs.String("out", "", "directory for fit and tcx files (default .), file for csv and json (default stdout)")
+	intervals := fs.Bool("intervals", false, "with csv or json, one row per interval instead of per session")
+	if positional, err := parseArgs(fs, args); err != nil {
+		return 2
+	} else if len(positional) > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	*format = strings.ToLower(*format)
+	activity := export.IsActivityFormat(*format)
+	if !activity && *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		return 2
+	}
+
+	sessions, err := history.Load(history.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *since != "" {
+		from, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid date %q, expected YYYY-MM-DD\n", *since)
+			return 2
+		}
+		sessions = history.Since(sessions, from)
+	} else if activity && len(sessions) > 0 {
+		sessions = sessions[len(sessions)-1:]
+	}
+
+	if !activity {
+		w := io.Writer(os.Stdout)
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := export.Write(w, *format, sessions, *intervals); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(sessions) == 0 {
+		fmt.Fprintln(os.Stderr, "No sessions to export")
+		return 1
+	}
+	if *out == "" {
+		*out = "."
+	}
+	for _, s := range sessions {
+		path, err := export.SaveSession(*out, *format, s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(path)
+	}
+	return 0
+}
+
+// start sets up sound, theme and integrations, then runs t in the TUI or
+// headless. A nil t opens the TUI on the clock.
+func (o *options) start(t *timer.Timer) int {
+	cfg, err := config.Load(o.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	// Flags override the config file
+	theme := cfg.Theme
+	if o.theme != "" {
+		theme = o.theme
+	}
+	if theme != "" {
+		if err := ui.ApplyTheme(theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	sound := cfg.Sound == nil || *cfg.Sound
+	switch strings.ToLower(o.sound) {
+	case "":
+	case "on", "true", "1":
+		sound = true
+	case "off", "false", "0":
+		sound = false
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --sound must be on or off, got %q\n", o.sound)
+		return 2
+	}
+
+	assetsDir := cfg.AssetsDir
+	if o.assetsDir != "" {
+		assetsDir = o.assetsDir
+	}
+	audioPlayer := newAudioPlayer(assetsDir)
+	audioPlayer.SetEnabled(sound)
+
+	var at time.Time
+	if o.at != "" {
+		if t == nil {
+			fmt.Fprintln(os.Stderr, "Error: --at needs a workout")
+			return 2
+		}
+		if at, err = parseStartTime(o.at, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	var plan *schedule.Schedule
+	schedulePath := cfg.Schedule
+	if o.schedule != "" {
+		schedulePath = o.schedule
+	}
+	if t == nil && schedulePath != "" {
+		if plan, err = schedule.Load(schedulePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading schedule: %v\n", err)
+			return 1
+		}
+		for _, w := range plan.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+
+	if !o.headless {
+		if err := runTUI(t, at, o, plan, cfg, audioPlayer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if t == nil {
+		fmt.Fprintln(os.Stderr, "Error: --headless needs a workout")
+		return 2
+	}
+	if o.heat != nil {
+		fmt.Fprintln(os.Stderr, "Error: heats need the terminal UI to capture finishes")
+		return 2
+	}
+	if err := headless.Check(t); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	dispatcher := events.NewDispatcher()
+	if o.json {
+		dispatcher.Add(events.NewJSONWriter(os.Stdout))
+	} else {
+		dispatcher.Add(events.NewTextWriter(os.Stdout))
+	}
+	cleanup := setupIntegrations(cfg, dispatcher, audioPlayer, nil, nil)
+	defer cleanup()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !at.IsZero() {
+		err = headless.WaitUntil(ctx, at, t, audioPlayer, dispatcher)
+	}
+	if err == nil {
+		err = headless.Run(ctx, t, audioPlayer, dispatcher)
+	}
+	if errors.Is(err, context.Canceled) {
+		return 130
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// leaderboardCommand implements `gymtimer leaderboard`, which keeps the
+// results of an in-house competition
+func leaderboardCommand(o *options, args []string) int {
+	fs := newFlagSet("leaderboard", o)
+	fs.StringVar(&o.leaderboard, "file", "", "competition results file")
+	scoring := fs.String("scoring", "time", "with workout: time, reps, rounds+reps or load")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	if o.leaderboard == "" {
+		if cfg, err := config.Load(o.configPath); err == nil && cfg.Leaderboard != "" {
+			o.leaderboard = cfg.Leaderboard
+		} else {
+			o.leaderboard = leaderboard.DefaultPath()
+		}
+	}
+	c, err := leaderboard.Load(o.leaderboard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	action := "print"
+	if len(positional) > 0 {
+		action, positional = positional[0], positional[1:]
+	}
+	wantArgs := map[string]int{"print": 0, "show": 0, "workout": 1, "score": 3, "points": 1, "name": 1}
+	n, ok := wantArgs[action]
+	if !ok || len(positional) != n {
+		fs.Usage()
+		return 2
+	}
+
+	switch action {
+	case "print":
+		printStandings(os.Stdout, c)
+		return 0
+	case "show":
+		o.showBoard = true
+		return o.start(nil)
+	case "workout":
+		kind, err := score.ParseKind(*scoring)
+		if err == nil {
+			err = c.AddWorkout(positional[0], kind)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	case "score":
+		w, ok := c.Workout(positional[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no workout %q; add it with \"leaderboard workout\"\n", positional[0])
+			return 2
+		}
+		sc, err := leaderboard.ParseResult(w, positional[2])
+		if err == nil {
+			err = c.Record(w.Name, positional[1], sc, time.Now())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	case "points":
+		c.Points = nil
+		if positional[0] != "places" {
+			for _, field := range strings.Split(positional[0], ",") {
+				p, err := strconv.Atoi(strings.TrimSpace(field))
+				if err != nil || p < 0 {
+					fmt.Fprintf(os.Stderr, "Error: invalid points %q\n", field)
+					return 2
+				}
+				c.Points = append(c.Points, p)
+			}
+		}
+	case "name":
+		c.Name = positional[0]
+	}
+
+	if err := c.Save(o.leaderboard); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	printStandings(os.Stdout, c)
+	return 0
+}
+
+// printStandings writes the overall standings with each athlete's place
+// and score per workout
+func printStandings(w io.Writer, c *leaderboard.Competition) {
+	if c.Name != "" {
+		fmt.Fprintln(w, c.Name)
+	}
+	if len(c.Workouts) == 0 {
+		fmt.Fprintln(w, "No workouts yet")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "RANK\tATHLETE\tPOINTS")
+	for _, wk := range c.Workouts {
+		fmt.Fprintf(tw, "\t%s", strings.ToUpper(wk.Name))
+	}
+	fmt.Fprintln(tw)
+	for _, s := range c.Standings() {
+		fmt.Fprintf(tw, "%d\t%s\t%d", s.Rank, s.Athlete, s.Total)
+		for _, p := range s.Placings {
+			if p.Score == nil {
+				fmt.Fprint(tw, "\t-")
+			} else {
+				fmt.Fprintf(tw, "\t%s (%s)", heat.Ordinal(p.Place), p.Score)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
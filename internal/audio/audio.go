@@ -1,6 +1,7 @@
 package audio
 
 import (
+	"errors"
 	"math"
 	"os"
 	"os/exec"
@@ -15,6 +16,8 @@ type Player struct {
 	mu        sync.Mutex
 	useAplay  bool
 	usePaplay bool
+	onError   func(err error)
+	playing   sync.WaitGroup
 }
 
 // New creates a new audio player
@@ -42,6 +45,13 @@ func (p *Player) SetEnabled(enabled bool) {
 	p.enabled = enabled
 }
 
+// SetErrorHandler registers a function called when a sound fails to play
+func (p *Player) SetErrorHandler(fn func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = fn
+}
+
 // IsEnabled returns whether audio is enabled
 func (p *Player) IsEnabled() bool {
 	p.mu.Lock()
@@ -58,7 +68,7 @@ func (p *Player) PlayBeep() {
 	}
 	p.mu.Unlock()
 
-	go p.playSound(p.beepPath)
+	p.start(p.beepPath)
 }
 
 // PlayCountdown plays a countdown beep for 3-2-1
@@ -70,7 +80,7 @@ func (p *Player) PlayCountdown(secondsRemaining int) {
 	}
 	p.mu.Unlock()
 
-	go p.playSound(p.beepPath)
+	p.start(p.beepPath)
 }
 
 // PlayChime plays the chime sound for interval changes
@@ -82,7 +92,7 @@ func (p *Player) PlayChime() {
 	}
 	p.mu.Unlock()
 
-	go p.playSound(p.chimePath)
+	p.start(p.chimePath)
 }
 
 // PlayIntervalChange plays chime for work/rest transition (at 0)
@@ -90,11 +100,61 @@ func (p *Player) PlayIntervalChange(isWork bool) {
 	p.PlayChime()
 }
 
+// PlayChangeover plays a beep followed by the chime when the next athlete
+// takes over
+func (p *Player) PlayChangeover() {
+	p.mu.Lock()
+	if !p.enabled {
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	p.sequence(p.beepPath, p.chimePath)
+}
+
+// PlaySetEnd plays the chime twice when a set ends and the rest between
+// sets begins
+func (p *Player) PlaySetEnd() {
+	p.mu.Lock()
+	if !p.enabled {
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	p.sequence(p.chimePath, p.chimePath)
+}
+
+// sequence plays the sounds one after another in the background
+func (p *Player) sequence(paths ...string) {
+	p.playing.Add(1)
+	go func() {
+		defer p.playing.Done()
+		for _, path := range paths {
+			p.playSound(path)
+		}
+	}()
+}
+
 // PlayFinish plays the chime for completion
 func (p *Player) PlayFinish() {
 	p.PlayChime()
 }
 
+// Wait blocks until every sound that has been started finishes playing
+func (p *Player) Wait() {
+	p.playing.Wait()
+}
+
+func (p *Player) start(path string) {
+	p.playing.Add(1)
+	go func() {
+		defer p.playing.Done()
+		p.playSound(path)
+	}()
+}
+
 func (p *Player) playSound(path string) {
 	var cmd *exec.Cmd
 
@@ -103,11 +163,23 @@ func (p *Player) playSound(path string) {
 	} else if p.useAplay {
 		cmd = exec.Command("aplay", "-q", path)
 	} else {
-		// No audio player available
+		p.fail(errors.New("no audio player available (install paplay or aplay)"))
 		return
 	}
 
-	_ = cmd.Start()
+	if err := cmd.Run(); err != nil {
+		p.fail(err)
+	}
+}
+
+func (p *Player) fail(err error) {
+	p.mu.Lock()
+	fn := p.onError
+	p.mu.Unlock()
+
+	if fn != nil {
+		fn(err)
+	}
 }
 
 // GenerateBeepWAV generates a short beep for countdown (880Hz, 150ms)
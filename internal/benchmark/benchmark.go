@@ -0,0 +1,124 @@
+// Package benchmark holds named benchmark workouts such as "Fran", each
+// with its timer and how its result is scored
+package benchmark
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/score"
+	"gymtimer/internal/workout"
+)
+
+// Benchmark is a named workout with a scoring type
+type Benchmark struct {
+	Workout workout.Workout
+	Scoring score.Kind
+}
+
+// Name returns the benchmark's name, e.g. "Fran"
+func (b Benchmark) Name() string {
+	return b.Workout.Name
+}
+
+func minutes(n int) config.Duration {
+	return config.Duration(time.Duration(n) * time.Minute)
+}
+
+// Builtin lists the classic benchmark workouts
+var Builtin = []Benchmark{
+	{workout.Workout{Name: "Fran", Mode: "fortime", Duration: minutes(10),
+		Description: "21-15-9 thrusters (95/65 lb) and pull-ups"}, score.Time},
+	{workout.Workout{Name: "Grace", Mode: "fortime", Duration: minutes(10),
+		Description: "30 clean and jerks (135/95 lb)"}, score.Time},
+	{workout.Workout{Name: "Isabel", Mode: "fortime", Duration: minutes(10),
+		Description: "30 snatches (135/95 lb)"}, score.Time},
+	{workout.Workout{Name: "Diane", Mode: "fortime", Duration: minutes(15),
+		Description: "21-15-9 deadlifts (225/155 lb) and handstand push-ups"}, score.Time},
+	{workout.Workout{Name: "Helen", Mode: "fortime", Duration: minutes(20),
+		Description: "3 rounds: 400 m run, 21 kettlebell swings, 12 pull-ups"}, score.Time},
+	{workout.Workout{Name: "Annie", Mode: "fortime", Duration: minutes(20),
+		Description: "50-40-30-20-10 double-unders and sit-ups"}, score.Time},
+	{workout.Workout{Name: "Karen", Mode: "fortime", Duration: minutes(20),
+		Description: "150 wall-ball shots (20/14 lb)"}, score.Time},
+	{workout.Workout{Name: "Murph", Mode: "fortime", Duration: minutes(75),
+		Description: "1 mile run, 100 pull-ups, 200 push-ups, 300 squats, 1 mile run"}, score.Time},
+	{workout.Workout{Name: "Cindy", Mode: "amrap", Duration: minutes(20),
+		Description: "5 pull-ups, 10 push-ups, 15 air squats"}, score.RoundsReps},
+	{workout.Workout{Name: "Mary", Mode: "amrap", Duration: minutes(20),
+		Description: "5 handstand push-ups, 10 pistols, 15 pull-ups"}, score.RoundsReps},
+	{workout.Workout{Name: "Chelsea", Mode: "emom", Every: minutes(1), Rounds: 30,
+		Description: "Every minute: 5 pull-ups, 10 push-ups, 15 air squats; score rounds completed"}, score.Reps},
+	{workout.Workout{Name: "Tabata Something Else", Mode: "tabata", Rounds: 32,
+		Description: "8 rounds each of pull-ups, push-ups, sit-ups and squats; score total reps"}, score.Reps},
+}
+
+// FromWorkout makes a benchmark of a workout file that names its scoring
+func FromWorkout(w workout.Workout) (Benchmark, bool, error) {
+	if w.Scoring == "" {
+		return Benchmark{}, false, nil
+	}
+	if w.Name == "" {
+		return Benchmark{}, false, fmt.Errorf("a scored workout needs a name")
+	}
+	kind, err := score.ParseKind(w.Scoring)
+	if err != nil {
+		return Benchmark{}, false, err
+	}
+	if _, err := w.Timer(); err != nil {
+		return Benchmark{}, false, err
+	}
+	return Benchmark{Workout: w, Scoring: kind}, true, nil
+}
+
+// Dir returns where custom benchmark workout files live
+func Dir() string {
+	return filepath.Join(config.Dir(), "benchmarks")
+}
+
+// Library returns the built-in benchmarks followed by the custom ones in
+// dir, sorted by name. A custom benchmark replaces a built-in one of the
+// same name.
+func Library(dir string) ([]Benchmark, error) {
+	byName := make(map[string]Benchmark)
+	for _, b := range Builtin {
+		byName[strings.ToLower(b.Name())] = b
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		w, err := workout.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		b, ok, err := FromWorkout(w)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: a benchmark needs a scoring type", path)
+		}
+		byName[strings.ToLower(b.Name())] = b
+	}
+
+	lib := make([]Benchmark, 0, len(byName))
+	for _, b := range byName {
+		lib = append(lib, b)
+	}
+	sort.Slice(lib, func(i, j int) bool { return lib[i].Name() < lib[j].Name() })
+	return lib, nil
+}
+
+// Find looks up a benchmark by name, ignoring case
+func Find(lib []Benchmark, name string) (Benchmark, bool) {
+	for _, b := range lib {
+		if strings.EqualFold(b.Name(), name) {
+			return b, true
+		}
+	}
+	return Benchmark{}, false
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Config holds user settings loaded from the config file
+type Config struct {
+	Sound     *bool  `json:"sound"`      // Defaults to on
+	Theme     string `json:"theme"`      // See ui.Themes
+	AssetsDir string `json:"assets_dir"` // Where beep.wav and chime.wav live
+	Schedule  string `json:"schedule"`   // Day plan shown on the clock, JSON or .ics
+
+	ExportDir    string `json:"export_dir"`    // Where the finished screen exports to
+	ExportFormat string `json:"export_format"` // csv (default), json, fit or tcx
+	Leaderboard  string `json:"leaderboard"`   // Competition results file
+
+	Hooks    []Hook    `json:"hooks"`
+	Webhooks []Webhook `json:"webhooks"`
+	MQTT     *MQTT     `json:"mqtt"`
+	OSC      *OSC      `json:"osc"`
+	Metrics  *Metrics  `json:"metrics"`
+}
+
+// Hook runs a shell command when matching timer events occur
+type Hook struct {
+	On      []string `json:"on"`      // Event kinds to react to; empty means all
+	Command string   `json:"command"` // Passed to sh -c
+	Timeout Duration `json:"timeout"` // Defaults to 10s
+}
+
+// Webhook POSTs timer events as JSON to a URL
+type Webhook struct {
+	URL     string   `json:"url"`
+	On      []string `json:"on"`      // Event kinds to send; empty means all
+	Secret  string   `json:"secret"`  // Signs the body with HMAC-SHA256 when set
+	Timeout Duration `json:"timeout"` // Per request, defaults to 5s
+	Retries *int     `json:"retries"` // Extra attempts after a failure, defaults to 3; 0 for none
+}
+
+// MQTT connects the timer to a broker for publishing state and receiving
+// commands
+type MQTT struct {
+	Broker       string   `json:"broker"` // e.g. tcp://192.168.1.10:1883
+	ClientID     string   `json:"client_id"`
+	Username     string   `json:"username"`
+	Password     string   `json:"password"`
+	QoS          byte     `json:"qos"`
+	On           []string `json:"on"`            // Event kinds to publish; empty means all
+	EventTopic   string   `json:"event_topic"`   // Defaults to gymtimer/event
+	StateTopic   string   `json:"state_topic"`   // Defaults to gymtimer/state
+	CommandTopic string   `json:"command_topic"` // Defaults to gymtimer/command
+}
+
+// OSC sends Open Sound Control messages over UDP and listens for commands
+type OSC struct {
+	Listen  string   `json:"listen"`  // UDP address for commands, e.g. ":9000"; empty disables
+	Targets []string `json:"targets"` // host:port pairs to send events to
+	Prefix  string   `json:"prefix"`  // Address prefix, defaults to /gymtimer
+	On      []string `json:"on"`      // Event kinds to send; empty means all
+}
+
+// Metrics exposes Prometheus/OpenMetrics counters over HTTP
+type Metrics struct {
+	Listen string `json:"listen"` // e.g. ":9100", served at /metrics
+}
+
+// Duration is a time.Duration that reads and writes strings like "20s"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string such as "1m30s"
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Dir returns the gymtimer configuration directory
+func Dir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "gymtimer")
+}
+
+// DefaultPath returns the default config file location
+func DefaultPath() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,38 @@
+// Package cue turns what happens on each tick of a running timer into
+// sounds and events, the same way for the terminal UI and headless mode
+package cue
+
+import (
+	"gymtimer/internal/audio"
+	"gymtimer/internal/events"
+	"gymtimer/internal/timer"
+)
+
+// Step plays the sound for a step of t, as returned by Advance, and emits
+// the tick event followed by either the finish or the phase and round
+// changes
+func Step(t *timer.Timer, step timer.Step, player *audio.Player, dispatcher *events.Dispatcher) {
+	switch {
+	case step.RoundChanged && !step.Finished && t.Relay():
+		player.PlayChangeover()
+	case step.SetEnded:
+		player.PlaySetEnd()
+	case step.PhaseChanged || step.RoundChanged:
+		player.PlayIntervalChange(t.Phase == timer.PhaseWork)
+	}
+	if step.Finished {
+		player.PlayFinish()
+	}
+
+	dispatcher.Emit(events.New(events.KindTick, t))
+	if step.Finished {
+		dispatcher.Emit(events.New(events.KindFinish, t))
+		return
+	}
+	if step.PhaseChanged {
+		dispatcher.Emit(events.New(events.KindPhase, t))
+	}
+	if step.RoundChanged {
+		dispatcher.Emit(events.New(events.KindRound, t))
+	}
+}
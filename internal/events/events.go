@@ -0,0 +1,124 @@
+package events
+
+import (
+	"strings"
+	"time"
+
+	"gymtimer/internal/timer"
+)
+
+// Kind identifies the type of timer event
+type Kind string
+
+const (
+	KindStart     Kind = "start"
+	KindPause     Kind = "pause"
+	KindResume    Kind = "resume"
+	KindPhase     Kind = "phase"
+	KindRound     Kind = "round"
+	KindCountdown Kind = "countdown"
+	KindFinish    Kind = "finish"
+	KindTick      Kind = "tick" // Every second while the timer runs
+)
+
+// Event describes something that happened to the timer
+type Event struct {
+	Kind        Kind      `json:"event"`
+	Time        time.Time `json:"time"`
+	Mode        string    `json:"mode"`
+	Phase       string    `json:"phase"`
+	Round       int       `json:"round,omitempty"`
+	TotalRounds int       `json:"total_rounds,omitempty"`
+	Remaining   int       `json:"remaining"` // Seconds left in the current interval
+	Countdown   int       `json:"countdown,omitempty"`
+	Workout     string    `json:"workout,omitempty"`  // Summary, on start, resume and finish
+	Name        string    `json:"name,omitempty"`     // Workout name, on start, resume and finish
+	Athlete     string    `json:"athlete,omitempty"`  // Who is working, when athletes take turns
+	Movement    string    `json:"movement,omitempty"` // What the current round is, when labelled
+	Reps        int       `json:"reps,omitempty"`     // Death by: the reps due this minute
+	Set         int       `json:"set,omitempty"`      // Tabata and custom sets
+	TotalSets   int       `json:"total_sets,omitempty"`
+}
+
+// New builds an event of the given kind from the current timer state
+func New(kind Kind, t *timer.Timer) Event {
+	e := Event{
+		Kind:      kind,
+		Time:      time.Now(),
+		Mode:      strings.ToLower(t.ModeName()),
+		Phase:     strings.ToLower(t.PhaseName()),
+		Remaining: int(t.TimeRemaining().Seconds()),
+	}
+	if kind == KindStart || kind == KindResume || kind == KindFinish {
+		e.Workout = t.Summary()
+		e.Name = t.Name
+	}
+	if t.HasRounds() {
+		e.Round = t.Round
+		e.TotalRounds = t.TotalRounds
+	}
+	if t.Relay() {
+		e.Athlete = t.CurrentAthlete()
+	}
+	if t.HasSets() {
+		e.Set = t.SetOf(min(t.Round, t.TotalRounds))
+		e.TotalSets = t.Sets
+	}
+	if t.Mode == timer.ModeDeathBy {
+		e.Reps = t.RepTarget(min(t.Round, t.TotalRounds))
+	}
+	if t.HasMovements() {
+		mv, _ := t.CurrentMovement()
+		e.Movement = mv.Label
+	}
+	if t.IsFinished() {
+		// The round counter has moved past the last round
+		e.Remaining = 0
+		e.Round = min(e.Round, e.TotalRounds)
+	}
+	return e
+}
+
+// Matches reports whether kind is in the filter list. An empty list matches
+// every kind except ticks, which must be asked for explicitly.
+func Matches(filter []string, kind Kind) bool {
+	if len(filter) == 0 {
+		return kind != KindTick
+	}
+	for _, f := range filter {
+		if Kind(f) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Sink receives timer events. Send must not block.
+type Sink interface {
+	Send(e Event)
+}
+
+// Dispatcher fans events out to registered sinks
+type Dispatcher struct {
+	sinks []Sink
+}
+
+// NewDispatcher creates an empty dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Add registers a sink
+func (d *Dispatcher) Add(s Sink) {
+	d.sinks = append(d.sinks, s)
+}
+
+// Emit sends the event to every sink. A nil dispatcher drops events.
+func (d *Dispatcher) Emit(e Event) {
+	if d == nil {
+		return
+	}
+	for _, s := range d.sinks {
+		s.Send(e)
+	}
+}
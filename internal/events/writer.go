@@ -0,0 +1,58 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Writer is a sink that prints one line per event
+type Writer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	json bool
+}
+
+// NewJSONWriter prints each event as a single line of JSON
+func NewJSONWriter(w io.Writer) *Writer {
+	return &Writer{w: w, json: true}
+}
+
+// NewTextWriter prints each event as a short human readable line
+func NewTextWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Send writes the event
+func (w *Writer) Send(e Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.json {
+		json.NewEncoder(w.w).Encode(e)
+		return
+	}
+
+	line := fmt.Sprintf("%s %-9s %s %s", e.Time.Format("15:04:05"), e.Kind, e.Mode, e.Phase)
+	if e.TotalSets > 0 {
+		line += fmt.Sprintf(" set %d/%d", e.Set, e.TotalSets)
+	}
+	if e.TotalRounds > 0 {
+		line += fmt.Sprintf(" round %d/%d", e.Round, e.TotalRounds)
+	}
+	if e.Athlete != "" {
+		line += " " + e.Athlete
+	}
+	if e.Reps > 0 {
+		line += fmt.Sprintf(" %d reps", e.Reps)
+	}
+	if e.Movement != "" {
+		line += fmt.Sprintf(" %q", e.Movement)
+	}
+	line += fmt.Sprintf(" %02d:%02d left", e.Remaining/60, e.Remaining%60)
+	if e.Countdown > 0 {
+		line += fmt.Sprintf(" (%d)", e.Countdown)
+	}
+	fmt.Fprintln(w.w, line)
+}
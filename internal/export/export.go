@@ -0,0 +1,64 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gymtimer/internal/history"
+)
+
+// IsActivityFormat reports whether format holds one session per file, as
+// FIT and TCX do, rather than a table of many
+func IsActivityFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "fit", "tcx":
+		return true
+	default:
+		return false
+	}
+}
+
+// Write writes sessions in format: csv or json tables, or a single fit or
+// tcx activity. Tables hold one row per interval if intervals is set.
+func Write(out io.Writer, format string, sessions []history.Session, intervals bool) error {
+	switch strings.ToLower(format) {
+	case "csv":
+		return WriteCSV(out, sessions, intervals)
+	case "json":
+		return WriteJSON(out, sessions, intervals)
+	case "fit", "tcx":
+		if len(sessions) != 1 {
+			return fmt.Errorf("%s files hold exactly one session", format)
+		}
+		if strings.EqualFold(format, "fit") {
+			return WriteFIT(out, sessions[0])
+		}
+		return WriteTCX(out, sessions[0])
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// SaveSession writes a session to its own file in dir, named after its
+// start time, and returns the path. Tables list its intervals.
+func SaveSession(dir, format string, s history.Session) (string, error) {
+	format = strings.ToLower(format)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "gymtimer-"+s.Start.Format("20060102-150405")+"."+format)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := Write(f, format, []history.Session{s}, true); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,224 @@
+// Package export writes recorded sessions in formats other tools read
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"gymtimer/internal/history"
+)
+
+// FIT timestamps count seconds from 1989-12-31 00:00 UTC
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// FIT global message numbers
+const (
+	mesgFileID   = 0
+	mesgSession  = 18
+	mesgLap      = 19
+	mesgRecord   = 20
+	mesgEvent    = 21
+	mesgActivity = 34
+)
+
+// FIT base types
+const (
+	fitEnum    = 0x00
+	fitUint16  = 0x84
+	fitUint32  = 0x86
+	fitUint32z = 0x8C
+)
+
+// FIT enum values used here
+const (
+	fileActivity         = 4
+	manufacturerDev      = 255
+	sportTraining        = 10
+	subSportCardio       = 26
+	eventTimer           = 0
+	eventLap             = 9
+	eventSession         = 8
+	eventActivity        = 26
+	eventTypeStart       = 0
+	eventTypeStop        = 1
+	eventTypeStopAll     = 4
+	intensityActive      = 0
+	intensityRest        = 1
+	lapTriggerTime       = 1
+	lapTriggerSessionEnd = 7
+)
+
+// fitField is one field of a message: its number, base type and value
+type fitField struct {
+	num   byte
+	base  byte
+	value uint32
+}
+
+func (f fitField) size() byte {
+	switch f.base {
+	case fitEnum:
+		return 1
+	case fitUint16:
+		return 2
+	default:
+		return 4
+	}
+}
+
+// fitWriter encodes messages, writing each message type's definition the
+// first time it is used. Every message of a type must have the same fields.
+type fitWriter struct {
+	buf     bytes.Buffer
+	defined map[uint16]byte // Global message number -> local type
+}
+
+func (w *fitWriter) message(global uint16, fields ...fitField) {
+	local, ok := w.defined[global]
+	if !ok {
+		local = byte(len(w.defined))
+		w.defined[global] = local
+
+		w.buf.WriteByte(0x40 | local) // Definition message
+		w.buf.WriteByte(0)            // Reserved
+		w.buf.WriteByte(0)            // Little endian
+		binary.Write(&w.buf, binary.LittleEndian, global)
+		w.buf.WriteByte(byte(len(fields)))
+		for _, f := range fields {
+			w.buf.Write([]byte{f.num, f.size(), f.base})
+		}
+	}
+
+	w.buf.WriteByte(local)
+	for _, f := range fields {
+		switch f.size() {
+		case 1:
+			w.buf.WriteByte(byte(f.value))
+		case 2:
+			binary.Write(&w.buf, binary.LittleEndian, uint16(f.value))
+		default:
+			binary.Write(&w.buf, binary.LittleEndian, f.value)
+		}
+	}
+}
+
+// WriteFIT encodes the session as a FIT activity file with one lap per
+// interval. Work intervals are active laps and rest intervals resting laps.
+func WriteFIT(out io.Writer, s history.Session) error {
+	w := &fitWriter{defined: make(map[uint16]byte)}
+
+	ts := func(t time.Time) uint32 { return uint32(t.Sub(fitEpoch) / time.Second) }
+	ms := func(d time.Duration) uint32 { return uint32(d / time.Millisecond) }
+	field := func(num, base byte, value uint32) fitField { return fitField{num, base, value} }
+
+	w.message(mesgFileID,
+		field(0, fitEnum, fileActivity),
+		field(1, fitUint16, manufacturerDev),
+		field(2, fitUint16, 1),
+		field(3, fitUint32z, uint32(s.Start.Unix())),
+		field(4, fitUint32, ts(s.Start)),
+	)
+	w.message(mesgEvent,
+		field(253, fitUint32, ts(s.Start)),
+		field(0, fitEnum, eventTimer),
+		field(1, fitEnum, eventTypeStart),
+	)
+
+	for i, iv := range s.Intervals {
+		w.message(mesgRecord, field(253, fitUint32, ts(iv.Start)))
+
+		intensity := uint32(intensityActive)
+		if iv.IsRest() {
+			intensity = intensityRest
+		}
+		trigger := uint32(lapTriggerTime)
+		if i == len(s.Intervals)-1 {
+			trigger = lapTriggerSessionEnd
+		}
+		w.message(mesgLap,
+			field(254, fitUint16, uint32(i)),
+			field(253, fitUint32, ts(iv.End)),
+			field(0, fitEnum, eventLap),
+			field(1, fitEnum, eventTypeStop),
+			field(2, fitUint32, ts(iv.Start)),
+			field(7, fitUint32, ms(iv.Elapsed())),
+			field(8, fitUint32, ms(iv.Active())),
+			field(23, fitEnum, intensity),
+			field(24, fitEnum, trigger),
+			field(25, fitEnum, sportTraining),
+			field(39, fitEnum, subSportCardio),
+		)
+	}
+	w.message(mesgRecord, field(253, fitUint32, ts(s.End)))
+
+	w.message(mesgEvent,
+		field(253, fitUint32, ts(s.End)),
+		field(0, fitEnum, eventTimer),
+		field(1, fitEnum, eventTypeStopAll),
+	)
+	w.message(mesgSession,
+		field(254, fitUint16, 0),
+		field(253, fitUint32, ts(s.End)),
+		field(0, fitEnum, eventSession),
+		field(1, fitEnum, eventTypeStop),
+		field(2, fitUint32, ts(s.Start)),
+		field(5, fitEnum, sportTraining),
+		field(6, fitEnum, subSportCardio),
+		field(7, fitUint32, ms(s.Elapsed())),
+		field(8, fitUint32, ms(s.Active())),
+		field(25, fitUint16, 0),
+		field(26, fitUint16, uint32(len(s.Intervals))),
+	)
+
+	_, offset := s.Start.Zone()
+	w.message(mesgActivity,
+		field(253, fitUint32, ts(s.End)),
+		field(0, fitUint32, ms(s.Active())),
+		field(1, fitUint16, 1),
+		field(2, fitEnum, 0), // Manual
+		field(3, fitEnum, eventActivity),
+		field(4, fitEnum, eventTypeStop),
+		field(5, fitUint32, uint32(int64(ts(s.End))+int64(offset))),
+	)
+
+	return writeFITFile(out, w.buf.Bytes())
+}
+
+// writeFITFile wraps the records in the file header and trailing CRC
+func writeFITFile(out io.Writer, records []byte) error {
+	var file bytes.Buffer
+	file.WriteByte(14)   // Header size
+	file.WriteByte(0x10) // Protocol version 1.0
+	binary.Write(&file, binary.LittleEndian, uint16(2132))
+	binary.Write(&file, binary.LittleEndian, uint32(len(records)))
+	file.WriteString(".FIT")
+	binary.Write(&file, binary.LittleEndian, fitCRC(file.Bytes()))
+
+	file.Write(records)
+	binary.Write(&file, binary.LittleEndian, fitCRC(file.Bytes()))
+
+	_, err := out.Write(file.Bytes())
+	return err
+}
+
+var crcTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCRC computes the CRC-16 used by FIT files
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := crcTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ crcTable[b&0xF]
+
+		tmp = crcTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ crcTable[(b>>4)&0xF]
+	}
+	return crc
+}
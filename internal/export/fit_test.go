@@ -0,0 +1,247 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"gymtimer/internal/history"
+)
+
+// Seconds between the Unix epoch and the FIT epoch, 1989-12-31 00:00 UTC
+const fitEpochUnix = 631065600
+
+// fitMessage is a decoded data message
+type fitMessage struct {
+	global uint16
+	fields map[byte]uint32
+}
+
+// crc16 is the CRC-16/ARC the FIT protocol uses, computed bit by bit so
+// it checks the table-driven encoder independently
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// decodeFIT checks the header and both CRCs and returns the data messages
+func decodeFIT(data []byte) ([]fitMessage, error) {
+	if len(data) < 16 {
+		return nil, fmt.Errorf("file of %d bytes is too short", len(data))
+	}
+	headerSize := int(data[0])
+	if headerSize != 14 {
+		return nil, fmt.Errorf("header size %d, want 14", headerSize)
+	}
+	if string(data[8:12]) != ".FIT" {
+		return nil, fmt.Errorf("signature %q, want .FIT", data[8:12])
+	}
+	if got, want := binary.LittleEndian.Uint16(data[12:14]), crc16(data[:12]); got != want {
+		return nil, fmt.Errorf("header CRC %#04x, want %#04x", got, want)
+	}
+	size := int(binary.LittleEndian.Uint32(data[4:8]))
+	if headerSize+size+2 != len(data) {
+		return nil, fmt.Errorf("data size %d does not fit a file of %d bytes", size, len(data))
+	}
+	end := headerSize + size
+	if got, want := binary.LittleEndian.Uint16(data[end:]), crc16(data[:end]); got != want {
+		return nil, fmt.Errorf("file CRC %#04x, want %#04x", got, want)
+	}
+
+	type fieldDef struct{ num, size, base byte }
+	type definition struct {
+		global uint16
+		fields []fieldDef
+	}
+	defs := map[byte]definition{}
+
+	var messages []fitMessage
+	r := bytes.NewReader(data[headerSize:end])
+	for r.Len() > 0 {
+		header, _ := r.ReadByte()
+		if header&0x80 != 0 {
+			return nil, fmt.Errorf("unexpected compressed timestamp header %#02x", header)
+		}
+		local := header & 0x0F
+
+		if header&0x40 != 0 {
+			var fixed [5]byte
+			if _, err := r.Read(fixed[:]); err != nil {
+				return nil, err
+			}
+			if fixed[1] != 0 {
+				return nil, fmt.Errorf("architecture %d, want little endian", fixed[1])
+			}
+			def := definition{global: binary.LittleEndian.Uint16(fixed[2:4])}
+			for i := 0; i < int(fixed[4]); i++ {
+				var f [3]byte
+				if _, err := r.Read(f[:]); err != nil {
+					return nil, err
+				}
+				def.fields = append(def.fields, fieldDef{f[0], f[1], f[2]})
+			}
+			defs[local] = def
+			continue
+		}
+
+		def, ok := defs[local]
+		if !ok {
+			return nil, fmt.Errorf("data message for undefined local type %d", local)
+		}
+		m := fitMessage{global: def.global, fields: map[byte]uint32{}}
+		for _, f := range def.fields {
+			buf := make([]byte, f.size)
+			if _, err := r.Read(buf); err != nil {
+				return nil, fmt.Errorf("message %d field %d: %w", def.global, f.num, err)
+			}
+			switch f.size {
+			case 1:
+				m.fields[f.num] = uint32(buf[0])
+			case 2:
+				m.fields[f.num] = uint32(binary.LittleEndian.Uint16(buf))
+			case 4:
+				m.fields[f.num] = binary.LittleEndian.Uint32(buf)
+			default:
+				return nil, fmt.Errorf("message %d field %d has size %d", def.global, f.num, f.size)
+			}
+		}
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
+
+// fitTime converts a time to a FIT timestamp
+func fitTime(t time.Time) uint32 {
+	return uint32(t.Unix() - fitEpochUnix)
+}
+
+// testSession is a two-round workout with a pause in the second work
+// interval
+func testSession() history.Session {
+	start := time.Date(2026, 10, 18, 6, 30, 0, 0, time.UTC)
+	at := func(secs int) time.Time { return start.Add(time.Duration(secs) * time.Second) }
+	return history.Session{
+		Mode:    "tabata",
+		Workout: "TABATA 2 x 20s/10s",
+		Start:   at(0),
+		End:     at(75),
+		Paused:  15 * time.Second,
+		Intervals: []history.Interval{
+			{Phase: "work", Round: 1, Start: at(0), End: at(20)},
+			{Phase: "rest", Round: 1, Start: at(20), End: at(30)},
+			{Phase: "work", Round: 2, Start: at(30), End: at(65), Paused: 15 * time.Second},
+			{Phase: "rest", Round: 2, Start: at(65), End: at(75)},
+		},
+	}
+}
+
+func TestWriteFIT(t *testing.T) {
+	s := testSession()
+	var buf bytes.Buffer
+	if err := WriteFIT(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	messages, err := decodeFIT(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byType := map[uint16][]fitMessage{}
+	for _, m := range messages {
+		byType[m.global] = append(byType[m.global], m)
+	}
+
+	if ids := byType[mesgFileID]; len(ids) != 1 || ids[0].fields[0] != fileActivity {
+		t.Errorf("file id %v, want one activity file", ids)
+	}
+
+	laps := byType[mesgLap]
+	if len(laps) != len(s.Intervals) {
+		t.Fatalf("%d laps, want one per interval (%d)", len(laps), len(s.Intervals))
+	}
+	for i, lap := range laps {
+		iv := s.Intervals[i]
+		if got := lap.fields[254]; got != uint32(i) {
+			t.Errorf("lap %d: message index %d", i, got)
+		}
+		if got, want := lap.fields[2], fitTime(iv.Start); got != want {
+			t.Errorf("lap %d: start time %d, want %d", i, got, want)
+		}
+		if got, want := lap.fields[253], fitTime(iv.End); got != want {
+			t.Errorf("lap %d: timestamp %d, want %d", i, got, want)
+		}
+		if got, want := lap.fields[7], uint32(iv.Elapsed().Milliseconds()); got != want {
+			t.Errorf("lap %d: elapsed %dms, want %d", i, got, want)
+		}
+		if got, want := lap.fields[8], uint32(iv.Active().Milliseconds()); got != want {
+			t.Errorf("lap %d: timer time %dms, want %d", i, got, want)
+		}
+		intensity := uint32(intensityActive)
+		if iv.IsRest() {
+			intensity = intensityRest
+		}
+		if got := lap.fields[23]; got != intensity {
+			t.Errorf("lap %d (%s): intensity %d, want %d", i, iv.Phase, got, intensity)
+		}
+	}
+	if got := laps[len(laps)-1].fields[24]; got != lapTriggerSessionEnd {
+		t.Errorf("last lap trigger %d, want session end", got)
+	}
+
+	sessions := byType[mesgSession]
+	if len(sessions) != 1 {
+		t.Fatalf("%d sessions, want 1", len(sessions))
+	}
+	session := sessions[0].fields
+	if got, want := session[2], fitTime(s.Start); got != want {
+		t.Errorf("session start %d, want %d", got, want)
+	}
+	if got := session[7]; got != 75000 {
+		t.Errorf("session elapsed %dms, want 75000", got)
+	}
+	if got := session[8]; got != 60000 {
+		t.Errorf("session timer time %dms, want 60000", got)
+	}
+	if got := session[26]; got != uint32(len(s.Intervals)) {
+		t.Errorf("session laps %d, want %d", got, len(s.Intervals))
+	}
+
+	activities := byType[mesgActivity]
+	if len(activities) != 1 || activities[0].fields[1] != 1 {
+		t.Errorf("activity %v, want one with one session", activities)
+	}
+	if got := len(byType[mesgEvent]); got != 2 {
+		t.Errorf("%d timer events, want start and stop", got)
+	}
+}
+
+func TestWriteFITCorrupt(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFIT(&buf, testSession()); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	data[len(data)/2] ^= 0xFF
+	if _, err := decodeFIT(data); err == nil {
+		t.Error("decoded a corrupted file without a CRC error")
+	}
+}
+
+func TestFITCRC(t *testing.T) {
+	// The CRC-16/ARC check value
+	if got := fitCRC([]byte("123456789")); got != 0xBB3D {
+		t.Errorf("fitCRC = %#04x, want 0xbb3d", got)
+	}
+}
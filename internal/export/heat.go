@@ -0,0 +1,92 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gymtimer/internal/heat"
+)
+
+// Column names are part of the output format; add new ones at the end
+var heatColumns = []string{"rank", "lane", "athlete", "result", "kind", "time_seconds"}
+
+type heatRow struct {
+	Rank        int     `json:"rank"`
+	Lane        int     `json:"lane"`
+	Athlete     string  `json:"athlete"`
+	Result      string  `json:"result"`
+	Kind        string  `json:"kind"`
+	TimeSeconds float64 `json:"time_seconds"`
+}
+
+func (r heatRow) record() []string {
+	rank := ""
+	if r.Rank > 0 {
+		rank = strconv.Itoa(r.Rank)
+	}
+	return []string{rank, strconv.Itoa(r.Lane), r.Athlete, r.Result, r.Kind, formatSeconds(r.TimeSeconds)}
+}
+
+func heatRows(results []heat.Result) []heatRow {
+	rows := make([]heatRow, 0, len(results))
+	for _, res := range results {
+		row := heatRow{Rank: res.Rank, Lane: res.Lane.Number, Athlete: res.Lane.Name()}
+		if sc := res.Lane.Score; sc != nil {
+			row.Result = sc.String()
+			row.Kind = string(sc.Kind)
+			row.TimeSeconds = seconds(sc.Time)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteHeat writes heat results in rank order as a csv or json table
+func WriteHeat(out io.Writer, format string, results []heat.Result) error {
+	switch strings.ToLower(format) {
+	case "csv":
+		w := csv.NewWriter(out)
+		w.Write(heatColumns)
+		for _, r := range heatRows(results) {
+			w.Write(r.record())
+		}
+		w.Flush()
+		return w.Error()
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(heatRows(results))
+	default:
+		return fmt.Errorf("heat results export as csv or json, not %q", format)
+	}
+}
+
+// SaveHeat writes the heat's results to a file in dir named after its start
+// time and returns the path. Activity formats fall back to csv.
+func SaveHeat(dir, format string, h *heat.Heat) (string, error) {
+	format = strings.ToLower(format)
+	if IsActivityFormat(format) {
+		format = "csv"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "heat-"+h.Start.Format("20060102-150405")+"."+format)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteHeat(f, format, h.Results()); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"gymtimer/internal/history"
+)
+
+// Column names are part of the output format; add new ones at the end
+var (
+	sessionColumns = []string{
+		"session_start", "session_end", "mode", "workout",
+		"elapsed_seconds", "active_seconds", "paused_seconds", "rounds", "intervals",
+	}
+	intervalColumns = []string{
+		"session_start", "mode", "workout", "interval", "round", "phase",
+		"start", "end", "elapsed_seconds", "active_seconds", "paused_seconds",
+	}
+)
+
+type sessionRow struct {
+	SessionStart   string  `json:"session_start"`
+	SessionEnd     string  `json:"session_end"`
+	Mode           string  `json:"mode"`
+	Workout        string  `json:"workout"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	ActiveSeconds  float64 `json:"active_seconds"`
+	PausedSeconds  float64 `json:"paused_seconds"`
+	Rounds         int     `json:"rounds"`
+	Intervals      int     `json:"intervals"`
+}
+
+type intervalRow struct {
+	SessionStart   string  `json:"session_start"`
+	Mode           string  `json:"mode"`
+	Workout        string  `json:"workout"`
+	Interval       int     `json:"interval"`
+	Round          int     `json:"round"`
+	Phase          string  `json:"phase"`
+	Start          string  `json:"start"`
+	End            string  `json:"end"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	ActiveSeconds  float64 `json:"active_seconds"`
+	PausedSeconds  float64 `json:"paused_seconds"`
+}
+
+func (r sessionRow) record() []string {
+	return []string{
+		r.SessionStart, r.SessionEnd, r.Mode, r.Workout,
+		formatSeconds(r.ElapsedSeconds), formatSeconds(r.ActiveSeconds), formatSeconds(r.PausedSeconds),
+		strconv.Itoa(r.Rounds), strconv.Itoa(r.Intervals),
+	}
+}
+
+func (r intervalRow) record() []string {
+	return []string{
+		r.SessionStart, r.Mode, r.Workout, strconv.Itoa(r.Interval), strconv.Itoa(r.Round), r.Phase,
+		r.Start, r.End,
+		formatSeconds(r.ElapsedSeconds), formatSeconds(r.ActiveSeconds), formatSeconds(r.PausedSeconds),
+	}
+}
+
+func sessionRows(sessions []history.Session) []sessionRow {
+	rows := make([]sessionRow, 0, len(sessions))
+	for _, s := range sessions {
+		rounds := 0
+		for _, iv := range s.Intervals {
+			rounds = max(rounds, iv.Round)
+		}
+		rows = append(rows, sessionRow{
+			SessionStart:   stamp(s.Start),
+			SessionEnd:     stamp(s.End),
+			Mode:           s.Mode,
+			Workout:        s.Workout,
+			ElapsedSeconds: seconds(s.Elapsed()),
+			ActiveSeconds:  seconds(s.Active()),
+			PausedSeconds:  seconds(s.Paused),
+			Rounds:         rounds,
+			Intervals:      len(s.Intervals),
+		})
+	}
+	return rows
+}
+
+func intervalRows(sessions []history.Session) []intervalRow {
+	rows := []intervalRow{}
+	for _, s := range sessions {
+		for i, iv := range s.Intervals {
+			rows = append(rows, intervalRow{
+				SessionStart:   stamp(s.Start),
+				Mode:           s.Mode,
+				Workout:        s.Workout,
+				Interval:       i + 1,
+				Round:          iv.Round,
+				Phase:          iv.Phase,
+				Start:          stamp(iv.Start),
+				End:            stamp(iv.End),
+				ElapsedSeconds: seconds(iv.Elapsed()),
+				ActiveSeconds:  seconds(iv.Active()),
+				PausedSeconds:  seconds(iv.Paused),
+			})
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes one row per session, or with intervals one row per
+// interval, under a header of column names
+func WriteCSV(out io.Writer, sessions []history.Session, intervals bool) error {
+	w := csv.NewWriter(out)
+	if intervals {
+		w.Write(intervalColumns)
+		for _, r := range intervalRows(sessions) {
+			w.Write(r.record())
+		}
+	} else {
+		w.Write(sessionColumns)
+		for _, r := range sessionRows(sessions) {
+			w.Write(r.record())
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// WriteJSON writes an array of objects with the same keys as the CSV
+// columns
+func WriteJSON(out io.Writer, sessions []history.Session, intervals bool) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if intervals {
+		return enc.Encode(intervalRows(sessions))
+	}
+	return enc.Encode(sessionRows(sessions))
+}
+
+func stamp(t time.Time) string {
+	return t.Local().Format(time.RFC3339)
+}
+
+// seconds rounds to the millisecond
+func seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', -1, 64)
+}
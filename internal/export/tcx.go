@@ -0,0 +1,85 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"gymtimer/internal/history"
+)
+
+const tcxNamespace = "http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2"
+
+type tcxDatabase struct {
+	XMLName    xml.Name      `xml:"TrainingCenterDatabase"`
+	Xmlns      string        `xml:"xmlns,attr"`
+	Activities []tcxActivity `xml:"Activities>Activity"`
+}
+
+type tcxActivity struct {
+	Sport string   `xml:"Sport,attr"`
+	ID    string   `xml:"Id"`
+	Laps  []tcxLap `xml:"Lap"`
+	Notes string   `xml:"Notes,omitempty"`
+}
+
+type tcxLap struct {
+	StartTime        string          `xml:"StartTime,attr"`
+	TotalTimeSeconds float64         `xml:"TotalTimeSeconds"`
+	DistanceMeters   float64         `xml:"DistanceMeters"`
+	Calories         int             `xml:"Calories"`
+	Intensity        string          `xml:"Intensity"`
+	TriggerMethod    string          `xml:"TriggerMethod"`
+	Track            []tcxTrackpoint `xml:"Track>Trackpoint"`
+	Notes            string          `xml:"Notes,omitempty"`
+}
+
+type tcxTrackpoint struct {
+	Time string `xml:"Time"`
+}
+
+// WriteTCX encodes the session as a Training Center activity with one lap
+// per interval
+func WriteTCX(out io.Writer, s history.Session) error {
+	stamp := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+	activity := tcxActivity{
+		Sport: "Other",
+		ID:    stamp(s.Start),
+		Notes: s.Workout,
+	}
+	for _, iv := range s.Intervals {
+		lap := tcxLap{
+			StartTime:        stamp(iv.Start),
+			TotalTimeSeconds: iv.Active().Round(time.Millisecond).Seconds(),
+			Intensity:        "Active",
+			TriggerMethod:    "Time",
+			Track:            []tcxTrackpoint{{stamp(iv.Start)}, {stamp(iv.End)}},
+			Notes:            lapNotes(iv),
+		}
+		if iv.IsRest() {
+			lap.Intensity = "Resting"
+		}
+		activity.Laps = append(activity.Laps, lap)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(tcxDatabase{Xmlns: tcxNamespace, Activities: []tcxActivity{activity}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+// lapNotes describes an interval, e.g. "Round 3 work"
+func lapNotes(iv history.Interval) string {
+	if iv.Round == 0 {
+		return iv.Phase
+	}
+	return fmt.Sprintf("Round %d %s", iv.Round, iv.Phase)
+}
@@ -0,0 +1,95 @@
+package headless
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gymtimer/internal/audio"
+	"gymtimer/internal/cue"
+	"gymtimer/internal/events"
+	"gymtimer/internal/timer"
+)
+
+// WaitUntil blocks until the scheduled start time, playing and emitting the
+// 3-2-1 lead-in for t along the way
+func WaitUntil(ctx context.Context, at time.Time, t *timer.Timer, player *audio.Player, dispatcher *events.Dispatcher) error {
+	for secs := 3; secs >= 0; secs-- {
+		beepAt := at.Add(-time.Duration(secs) * time.Second)
+		wait := time.Until(beepAt)
+		if wait < 0 {
+			continue
+		}
+
+		delay := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			delay.Stop()
+			return ctx.Err()
+		case <-delay.C:
+		}
+
+		if secs > 0 {
+			player.PlayCountdown(secs)
+			e := events.New(events.KindCountdown, t)
+			e.Phase = "get ready"
+			e.Countdown = secs
+			dispatcher.Emit(e)
+		}
+	}
+	return nil
+}
+
+// Check reports whether t can run headless. Workouts must end on their
+// own, since there is no key to press when the athlete is done.
+func Check(t *timer.Timer) error {
+	switch {
+	case t.Mode == timer.ModeClock || t.Mode == timer.ModeStopwatch:
+		return errors.New("headless mode needs a workout mode (emom, tabata, amrap or custom)")
+	case t.Mode == timer.ModeForTime && t.Duration <= 0:
+		return errors.New("for time needs a time cap (--cap) to run headless")
+	case t.Mode == timer.ModeDeathBy:
+		return errors.New("death by needs the terminal UI to record the failed minute")
+	}
+	return nil
+}
+
+// Run executes the timer without a UI, playing sounds and emitting events
+// once per second until the workout finishes or ctx is cancelled
+func Run(ctx context.Context, t *timer.Timer, player *audio.Player, dispatcher *events.Dispatcher) error {
+	if err := Check(t); err != nil {
+		return err
+	}
+
+	// Let the last sounds finish before the process exits
+	defer player.Wait()
+
+	t.Start()
+	dispatcher.Emit(events.New(events.KindStart, t))
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Pause()
+			dispatcher.Emit(events.New(events.KindPause, t))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if secs := t.CountdownBeep(); secs > 0 {
+			player.PlayCountdown(secs)
+			e := events.New(events.KindCountdown, t)
+			e.Countdown = secs
+			dispatcher.Emit(e)
+		}
+
+		step := t.Advance()
+		cue.Step(t, step, player, dispatcher)
+		if step.Finished {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package heat tracks a heat of athletes racing one clock, each in a lane
+package heat
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gymtimer/internal/score"
+)
+
+// MaxLanes is the most lanes a heat can have, one per number key 1-9 and 0
+const MaxLanes = 10
+
+// Lane is one athlete's place in the heat
+type Lane struct {
+	Number  int
+	Athlete string
+	Score   *score.Score // Finish time, or the score logged after the cap
+}
+
+// Name returns the athlete's name, or "Lane N" if none was given
+func (l Lane) Name() string {
+	if l.Athlete != "" {
+		return l.Athlete
+	}
+	return fmt.Sprintf("Lane %d", l.Number)
+}
+
+// Finished reports whether the athlete beat the clock with a finish time
+func (l Lane) Finished() bool {
+	return l.Score != nil && l.Score.Kind == score.Time
+}
+
+// Heat is a set of lanes and the order in which they finished
+type Heat struct {
+	Start time.Time
+	Lanes []Lane
+
+	captured []int // Lane numbers in the order their finish was captured
+}
+
+// New creates a heat with the given number of lanes, naming them after
+// athletes in order
+func New(lanes int, athletes []string) (*Heat, error) {
+	if lanes < 1 || lanes > MaxLanes {
+		return nil, fmt.Errorf("a heat has 1 to %d lanes, not %d", MaxLanes, lanes)
+	}
+	if len(athletes) > lanes {
+		return nil, fmt.Errorf("%d athletes for %d lanes", len(athletes), lanes)
+	}
+
+	h := &Heat{Lanes: make([]Lane, lanes)}
+	for i := range h.Lanes {
+		h.Lanes[i].Number = i + 1
+		if i < len(athletes) {
+			h.Lanes[i].Athlete = athletes[i]
+		}
+	}
+	return h, nil
+}
+
+// Lane returns the lane with the given number
+func (h *Heat) Lane(n int) (*Lane, bool) {
+	if n < 1 || n > len(h.Lanes) {
+		return nil, false
+	}
+	return &h.Lanes[n-1], true
+}
+
+// Finish records the lane's finish time, reporting false if the lane does
+// not exist or already finished
+func (h *Heat) Finish(n int, at time.Duration) bool {
+	l, ok := h.Lane(n)
+	if !ok || l.Finished() {
+		return false
+	}
+	l.Score = &score.Score{Kind: score.Time, Time: at}
+	h.captured = append(h.captured, n)
+	return true
+}
+
+// Undo clears the most recently captured finish and returns its lane
+func (h *Heat) Undo() (int, bool) {
+	if len(h.captured) == 0 {
+		return 0, false
+	}
+	n := h.captured[len(h.captured)-1]
+	h.captured = h.captured[:len(h.captured)-1]
+	h.Lanes[n-1].Score = nil
+	return n, true
+}
+
+// SetScore records the score of a lane that did not finish
+func (h *Heat) SetScore(n int, sc score.Score) bool {
+	l, ok := h.Lane(n)
+	if !ok || l.Finished() {
+		return false
+	}
+	l.Score = &sc
+	return true
+}
+
+// AllFinished reports whether every lane has a finish time
+func (h *Heat) AllFinished() bool {
+	for _, l := range h.Lanes {
+		if !l.Finished() {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset clears every result, keeping the lanes and athletes
+func (h *Heat) Reset() {
+	for i := range h.Lanes {
+		h.Lanes[i].Score = nil
+	}
+	h.captured = nil
+	h.Start = time.Time{}
+}
+
+// Result is a lane's place in the heat. Rank is 0 for lanes without a
+// result.
+type Result struct {
+	Rank int
+	Lane Lane
+}
+
+// Results ranks the lanes: finishers by time, then the rest by their
+// score, then those without one. Equal results share a rank.
+func (h *Heat) Results() []Result {
+	lanes := make([]Lane, len(h.Lanes))
+	copy(lanes, h.Lanes)
+	sort.SliceStable(lanes, func(i, j int) bool {
+		return better(lanes[i], lanes[j]) > 0
+	})
+
+	results := make([]Result, len(lanes))
+	for i, l := range lanes {
+		results[i].Lane = l
+		switch {
+		case l.Score == nil:
+		case i > 0 && better(l, lanes[i-1]) == 0:
+			results[i].Rank = results[i-1].Rank
+		default:
+			results[i].Rank = i + 1
+		}
+	}
+	return results
+}
+
+// Rank returns the lane's place, or 0 if it has no result yet
+func (h *Heat) Rank(n int) int {
+	for _, r := range h.Results() {
+		if r.Lane.Number == n {
+			return r.Rank
+		}
+	}
+	return 0
+}
+
+// better compares two lanes' results: positive if a placed ahead of b
+func better(a, b Lane) int {
+	switch {
+	case a.Score == nil && b.Score == nil:
+		return 0
+	case b.Score == nil:
+		return 1
+	case a.Score == nil:
+		return -1
+	default:
+		return score.Ahead(*a.Score, *b.Score)
+	}
+}
+
+// Ordinal formats a rank as "1st", "2nd", ...
+func Ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/score"
+)
+
+// Session is a finished workout
+type Session struct {
+	Mode      string        `json:"mode"`
+	Workout   string        `json:"workout"`        // Summary, e.g. "EMOM 12"
+	Name      string        `json:"name,omitempty"` // Named workouts only
+	Score     *score.Score  `json:"score,omitempty"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Paused    time.Duration `json:"paused"`
+	Intervals []Interval    `json:"intervals"`
+}
+
+// Interval is one phase of one round, e.g. the work part of round 3
+type Interval struct {
+	Phase  string        `json:"phase"`
+	Round  int           `json:"round,omitempty"`
+	Start  time.Time     `json:"start"`
+	End    time.Time     `json:"end"`
+	Paused time.Duration `json:"paused"`
+}
+
+// Elapsed returns the wall-clock length of the session
+func (s Session) Elapsed() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Active returns how long the timer ran, leaving out pauses
+func (s Session) Active() time.Duration {
+	return s.Elapsed() - s.Paused
+}
+
+// Elapsed returns the wall-clock length of the interval
+func (i Interval) Elapsed() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Active returns how long the timer ran during the interval
+func (i Interval) Active() time.Duration {
+	return i.Elapsed() - i.Paused
+}
+
+// IsRest reports whether the interval is a rest or get-ready phase
+func (i Interval) IsRest() bool {
+	return i.Phase == "rest" || i.Phase == "get ready"
+}
+
+// DefaultPath returns where finished sessions are kept
+func DefaultPath() string {
+	return filepath.Join(config.Dir(), "history.jsonl")
+}
+
+// Append adds a session to the history file, one JSON object per line
+func Append(path string, s Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return errors.Join(err, f.Close())
+}
+
+// Load reads every session in the history file, oldest first. A missing
+// file is an empty history.
+func Load(path string) ([]Session, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sessions []Session
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, scanner.Err()
+}
+
+// Title names the workout: its name if it has one, else its summary
+func (s Session) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Workout
+}
+
+// Best returns the best score of the given kind among sessions of the
+// workout titled title, or nil if there is none
+func Best(sessions []Session, title string, kind score.Kind) *score.Score {
+	var best *score.Score
+	for _, s := range sessions {
+		if s.Score == nil || s.Score.Kind != kind || s.Title() != title {
+			continue
+		}
+		if best == nil || s.Score.Compare(*best) > 0 {
+			best = s.Score
+		}
+	}
+	return best
+}
+
+// SetScore records the result of the session that started at start,
+// rewriting the history file
+func SetScore(path string, start time.Time, sc score.Score) error {
+	sessions, err := Load(path)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range sessions {
+		if sessions[i].Start.Equal(start) {
+			sessions[i].Score = &sc
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no session started at %s", start.Format(time.RFC3339))
+	}
+
+	var buf bytes.Buffer
+	for _, s := range sessions {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Since returns the sessions that started at or after t
+func Since(sessions []Session, t time.Time) []Session {
+	var out []Session
+	for _, s := range sessions {
+		if !s.Start.Before(t) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
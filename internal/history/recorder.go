@@ -0,0 +1,98 @@
+package history
+
+import (
+	"sync"
+	"time"
+
+	"gymtimer/internal/events"
+)
+
+// Recorder builds sessions from timer events and appends each finished one
+// to the history file. Workouts that are reset or abandoned are not kept. A
+// workout resumed after a restart, which this recorder never saw start, is
+// recorded from the resume on.
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	current  *Session
+	pausedAt time.Time
+
+	// OnRecord is called with each session once it is saved
+	OnRecord func(s Session)
+	// OnError is called when a session cannot be saved
+	OnError func(err error)
+}
+
+// NewRecorder creates a recorder that saves to path
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Send records a timer event
+func (r *Recorder) Send(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e.Kind == events.KindStart || (e.Kind == events.KindResume && r.current == nil) {
+		r.current = &Session{Mode: e.Mode, Workout: e.Workout, Name: e.Name, Start: e.Time}
+		r.pausedAt = time.Time{}
+		r.open(e)
+		return
+	}
+	if r.current == nil {
+		return
+	}
+
+	switch e.Kind {
+	case events.KindPause:
+		r.pausedAt = e.Time
+	case events.KindResume:
+		if !r.pausedAt.IsZero() {
+			paused := e.Time.Sub(r.pausedAt)
+			r.current.Paused += paused
+			r.current.Intervals[len(r.current.Intervals)-1].Paused += paused
+			r.pausedAt = time.Time{}
+		}
+	case events.KindPhase, events.KindRound:
+		// A new round may be announced as both a phase and a round change
+		last := r.current.Intervals[len(r.current.Intervals)-1]
+		if last.Phase == e.Phase && last.Round == e.Round {
+			return
+		}
+		r.close(e.Time)
+		r.open(e)
+	case events.KindFinish:
+		r.close(e.Time)
+		s := *r.current
+		s.End = e.Time
+		if e.Workout != "" {
+			s.Workout = e.Workout
+			s.Name = e.Name
+		}
+		r.current = nil
+
+		if err := Append(r.path, s); err != nil {
+			if r.OnError != nil {
+				r.OnError(err)
+			}
+			return
+		}
+		if r.OnRecord != nil {
+			r.OnRecord(s)
+		}
+	}
+}
+
+func (r *Recorder) open(e events.Event) {
+	r.current.Intervals = append(r.current.Intervals, Interval{
+		Phase: e.Phase,
+		Round: e.Round,
+		Start: e.Time,
+	})
+}
+
+func (r *Recorder) close(at time.Time) {
+	last := &r.current.Intervals[len(r.current.Intervals)-1]
+	last.End = at
+}
@@ -0,0 +1,102 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	maxConcurrent  = 8
+)
+
+// Runner executes configured hook commands on timer events
+type Runner struct {
+	hooks []config.Hook
+	slots chan struct{}
+
+	// OnError is called from the hook goroutine when a command fails
+	OnError func(err error)
+}
+
+// New creates a hook runner for the given hooks
+func New(hooks []config.Hook) *Runner {
+	return &Runner{
+		hooks: hooks,
+		slots: make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Send starts every matching hook in the background. If too many hooks are
+// still running the event is dropped rather than blocking the caller.
+func (r *Runner) Send(e events.Event) {
+	for _, h := range r.hooks {
+		if !events.Matches(h.On, e.Kind) {
+			continue
+		}
+
+		select {
+		case r.slots <- struct{}{}:
+		default:
+			r.fail(fmt.Errorf("hook %q skipped: too many hooks running", h.Command))
+			continue
+		}
+
+		go func(h config.Hook) {
+			defer func() { <-r.slots }()
+			if err := run(h, e); err != nil {
+				r.fail(fmt.Errorf("hook %q: %w", h.Command, err))
+			}
+		}(h)
+	}
+}
+
+func (r *Runner) fail(err error) {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+func run(h config.Hook, e events.Event) error {
+	timeout := time.Duration(h.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), env(e)...)
+	cmd.WaitDelay = time.Second
+
+	return cmd.Run()
+}
+
+// env exposes the event details as GYMTIMER_* variables
+func env(e events.Event) []string {
+	return []string{
+		"GYMTIMER_EVENT=" + string(e.Kind),
+		"GYMTIMER_MODE=" + e.Mode,
+		"GYMTIMER_PHASE=" + e.Phase,
+		"GYMTIMER_ROUND=" + strconv.Itoa(e.Round),
+		"GYMTIMER_TOTAL_ROUNDS=" + strconv.Itoa(e.TotalRounds),
+		"GYMTIMER_REMAINING=" + strconv.Itoa(e.Remaining),
+		"GYMTIMER_COUNTDOWN=" + strconv.Itoa(e.Countdown),
+		"GYMTIMER_TIME=" + e.Time.Format(time.RFC3339),
+	}
+}
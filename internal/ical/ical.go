@@ -0,0 +1,277 @@
+// Package ical reads the events of an iCalendar (.ics) file, enough to
+// build a class schedule: start times with time zones, recurrence rules,
+// excluded dates and moved or cancelled occurrences.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	// Calendars name IANA zones; carry the database so they resolve on
+	// machines without one installed
+	_ "time/tzdata"
+)
+
+// Event is a VEVENT
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	AllDay      bool // Start is a date without a time of day
+	Rule        *Rule
+	Exdates     []time.Time
+
+	// Set when the event repeats by a rule this package can't expand
+	// (see ErrUnsupported). Its occurrences are unknown, so it should be
+	// skipped.
+	Unsupported error
+
+	exdays []time.Time // Date-only EXDATEs, which remove that whole day
+}
+
+// Parse reads the events of a calendar. Moved occurrences of a recurring
+// event are returned as events of their own and excluded from the series;
+// cancelled events and occurrences are left out. Events repeating by a rule
+// that can't be expanded are returned with Unsupported set; only malformed
+// files fail.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		all       []Event
+		cancelled []bool
+		overrides = map[string][]time.Time{} // UID -> replaced occurrences
+		ev        *Event
+		recurID   time.Time
+		status    string
+		depth     int // Nesting inside the event, e.g. VALARM
+	)
+	for n, line := range lines {
+		name, params, value := parseLine(line)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			ev, recurID, status, depth = &Event{}, time.Time{}, "", 0
+			continue
+		case ev == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && value != "VEVENT":
+			depth--
+			continue
+		case name == "END":
+			if !recurID.IsZero() {
+				overrides[ev.UID] = append(overrides[ev.UID], recurID)
+				ev.Rule = nil
+			}
+			all = append(all, *ev)
+			cancelled = append(cancelled, status == "CANCELLED")
+			ev = nil
+			continue
+		case depth > 0:
+			continue
+		}
+
+		var err error
+		switch name {
+		case "UID":
+			ev.UID = value
+		case "SUMMARY":
+			ev.Summary = unescape(value)
+		case "DESCRIPTION":
+			ev.Description = unescape(value)
+		case "STATUS":
+			status = strings.ToUpper(value)
+		case "DTSTART":
+			ev.Start, ev.AllDay, err = parseTime(value, params)
+		case "RRULE":
+			ev.Rule, err = ParseRule(value)
+			if errors.Is(err, ErrUnsupported) {
+				ev.Unsupported, err = err, nil
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				var allDay bool
+				if t, allDay, err = parseTime(v, params); err != nil {
+					break
+				}
+				if allDay {
+					ev.exdays = append(ev.exdays, t)
+				} else {
+					ev.Exdates = append(ev.Exdates, t)
+				}
+			}
+		case "RECURRENCE-ID":
+			recurID, _, err = parseTime(value, params)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", n+1, name, err)
+		}
+	}
+
+	var events []Event
+	for i, e := range all {
+		if cancelled[i] || e.Start.IsZero() {
+			continue
+		}
+		if e.Rule != nil {
+			e.Exdates = append(e.Exdates, overrides[e.UID]...)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// Next returns the first occurrence of the event starting after t
+func (e Event) Next(t time.Time) (time.Time, bool) {
+	if e.Rule == nil {
+		return e.Start, e.Start.After(t)
+	}
+
+	var next time.Time
+	e.Rule.each(e.Start, func(occ time.Time) bool {
+		if !occ.After(t) || e.excluded(occ) {
+			return true
+		}
+		next = occ
+		return false
+	})
+	return next, !next.IsZero()
+}
+
+func (e Event) excluded(t time.Time) bool {
+	for _, ex := range e.Exdates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	for _, day := range e.exdays {
+		if sameDay(day, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameDay reports whether the occurrence t falls on day. A DATE has no
+// zone, so it is matched against t's own calendar date rather than the
+// local one.
+func sameDay(day, t time.Time) bool {
+	dy, dm, dd := day.Date()
+	ty, tm, td := t.Date()
+	return dy == ty && dm == tm && dd == td
+}
+
+// unfold joins continuation lines, which start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits "DTSTART;TZID=Europe/Paris:20260105T173000" into its
+// name, parameters and value
+func parseLine(line string) (string, map[string]string, string) {
+	// The value starts at the first colon outside a quoted parameter
+	inQuote := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuote = !inQuote
+		} else if c == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime reads a DATE or DATE-TIME value. UTC times end in Z, others are
+// in the TZID zone or, without one, local time.
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		loc = location(tzid)
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if v, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.ParseInLocation("20060102T150405", v, time.UTC)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// location resolves a TZID. Calendar apps sometimes prefix IANA names, e.g.
+// "/freeassociation.sourceforge.net/Europe/London"; unknown zones fall back
+// to local time.
+func location(tzid string) *time.Location {
+	tzid = strings.Trim(tzid, "/")
+	for {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			return loc
+		}
+		_, rest, ok := strings.Cut(tzid, "/")
+		if !ok {
+			return time.Local
+		}
+		tzid = rest
+	}
+}
+
+// unescape decodes TEXT values
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
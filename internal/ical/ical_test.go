@@ -0,0 +1,276 @@
+package ical
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseEvent reads a calendar holding a single event with the given
+// properties
+func parseEvent(t *testing.T, props ...string) Event {
+	t.Helper()
+	lines := append([]string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:test"}, props...)
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+	events, err := Parse(strings.NewReader(strings.Join(lines, "\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("parsed %d events, want 1", len(events))
+	}
+	return events[0]
+}
+
+// occurrences lists up to max occurrences of e in its own zone
+func occurrences(e Event, max int) []string {
+	var got []string
+	at := e.Start.Add(-time.Second)
+	for len(got) < max {
+		next, ok := e.Next(at)
+		if !ok {
+			break
+		}
+		got = append(got, next.Format("Mon 2006-01-02 15:04 -0700"))
+		at = next
+	}
+	return got
+}
+
+func TestRecurrence(t *testing.T) {
+	tests := []struct {
+		name  string
+		props []string
+		want  []string
+	}{
+		{
+			name:  "weekly by day",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=5"},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Wed 2026-01-07 18:00 +0100",
+				"Fri 2026-01-09 18:00 +0100",
+				"Mon 2026-01-12 18:00 +0100",
+				"Wed 2026-01-14 18:00 +0100",
+			},
+		},
+		{
+			name:  "weekly by day out of order",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260107T070000", "RRULE:FREQ=WEEKLY;BYDAY=FR,MO;COUNT=3"},
+			want: []string{
+				"Fri 2026-01-09 07:00 +0100",
+				"Mon 2026-01-12 07:00 +0100",
+				"Fri 2026-01-16 07:00 +0100",
+			},
+		},
+		{
+			name:  "every other week",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=4"},
+			want: []string{
+				"Tue 2026-01-06 18:00 +0100",
+				"Thu 2026-01-08 18:00 +0100",
+				"Tue 2026-01-20 18:00 +0100",
+				"Thu 2026-01-22 18:00 +0100",
+			},
+		},
+		{
+			name:  "every third day",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260129T060000", "RRULE:FREQ=DAILY;INTERVAL=3;COUNT=3"},
+			want: []string{
+				"Thu 2026-01-29 06:00 +0100",
+				"Sun 2026-02-01 06:00 +0100",
+				"Wed 2026-02-04 06:00 +0100",
+			},
+		},
+		{
+			name:  "count",
+			props: []string{"DTSTART:20260105T170000Z", "RRULE:FREQ=DAILY;COUNT=2"},
+			want: []string{
+				"Mon 2026-01-05 17:00 +0000",
+				"Tue 2026-01-06 17:00 +0000",
+			},
+		},
+		{
+			name:  "until includes its own time",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000", "RRULE:FREQ=DAILY;UNTIL=20260107T170000Z"},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Tue 2026-01-06 18:00 +0100",
+				"Wed 2026-01-07 18:00 +0100",
+			},
+		},
+		{
+			name:  "until before the last time",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000", "RRULE:FREQ=DAILY;UNTIL=20260107T165959Z"},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Tue 2026-01-06 18:00 +0100",
+			},
+		},
+		{
+			name: "count includes excluded dates",
+			props: []string{
+				"DTSTART;TZID=Europe/Berlin:20260105T180000",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"EXDATE;TZID=Europe/Berlin:20260112T180000",
+			},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Mon 2026-01-19 18:00 +0100",
+				"Mon 2026-01-26 18:00 +0100",
+			},
+		},
+		{
+			name: "exdate in UTC and as a date",
+			props: []string{
+				"DTSTART;TZID=Europe/Berlin:20260105T180000",
+				"RRULE:FREQ=DAILY;COUNT=5",
+				"EXDATE:20260106T170000Z",
+				"EXDATE;VALUE=DATE:20260108",
+			},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Wed 2026-01-07 18:00 +0100",
+				"Fri 2026-01-09 18:00 +0100",
+			},
+		},
+		{
+			name:  "exdate at another time is ignored",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000", "RRULE:FREQ=DAILY;COUNT=2", "EXDATE;TZID=Europe/Berlin:20260106T190000"},
+			want: []string{
+				"Mon 2026-01-05 18:00 +0100",
+				"Tue 2026-01-06 18:00 +0100",
+			},
+		},
+		{
+			name:  "month days that some months lack",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260131T090000", "RRULE:FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3"},
+			want: []string{
+				"Sat 2026-01-31 09:00 +0100",
+				"Tue 2026-03-31 09:00 +0200",
+				"Sun 2026-05-31 09:00 +0200",
+			},
+		},
+		{
+			name:  "leap day",
+			props: []string{"DTSTART:20240229T120000Z", "RRULE:FREQ=YEARLY;COUNT=2"},
+			want: []string{
+				"Thu 2024-02-29 12:00 +0000",
+				"Tue 2028-02-29 12:00 +0000",
+			},
+		},
+		{
+			name:  "spring forward",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260322T180000", "RRULE:FREQ=WEEKLY;COUNT=3"},
+			want: []string{
+				"Sun 2026-03-22 18:00 +0100",
+				"Sun 2026-03-29 18:00 +0200",
+				"Sun 2026-04-05 18:00 +0200",
+			},
+		},
+		{
+			name:  "fall back",
+			props: []string{"DTSTART;TZID=America/New_York:20261031T090000", "RRULE:FREQ=DAILY;COUNT=3"},
+			want: []string{
+				"Sat 2026-10-31 09:00 -0400",
+				"Sun 2026-11-01 09:00 -0500",
+				"Mon 2026-11-02 09:00 -0500",
+			},
+		},
+		{
+			name:  "prefixed zone",
+			props: []string{"DTSTART;TZID=/freeassociation.sourceforge.net/Europe/London:20261024T073000", "RRULE:FREQ=WEEKLY;COUNT=2"},
+			want: []string{
+				"Sat 2026-10-24 07:30 +0100",
+				"Sat 2026-10-31 07:30 +0000",
+			},
+		},
+		{
+			name:  "single event",
+			props: []string{"DTSTART;TZID=Europe/Berlin:20260105T180000"},
+			want:  []string{"Mon 2026-01-05 18:00 +0100"},
+		},
+	}
+	for _, tt := range tests {
+		got := occurrences(parseEvent(t, tt.props...), 10)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUntilDate(t *testing.T) {
+	// A date UNTIL and a floating start are both local time, so the last
+	// day is included wherever the test runs
+	e := parseEvent(t, "DTSTART:20260105T233000", "RRULE:FREQ=DAILY;UNTIL=20260106")
+	if got := occurrences(e, 10); len(got) != 2 {
+		t.Errorf("got %q, want the 5th and 6th", got)
+	}
+}
+
+func TestOpenEnded(t *testing.T) {
+	e := parseEvent(t, "DTSTART;TZID=Europe/Berlin:20200106T063000", "RRULE:FREQ=WEEKLY;BYDAY=MO,TH")
+	after := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	next, ok := e.Next(after)
+	if want := "Mon 2026-10-19 06:30 +0200"; !ok || next.Format("Mon 2006-01-02 15:04 -0700") != want {
+		t.Errorf("Next = %v, %v, want %s", next, ok, want)
+	}
+}
+
+func TestMovedOccurrence(t *testing.T) {
+	cal := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:wod",
+		"DTSTART;TZID=Europe/Berlin:20260105T180000",
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:wod",
+		"RECURRENCE-ID;TZID=Europe/Berlin:20260106T180000",
+		"DTSTART;TZID=Europe/Berlin:20260106T190000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	events, err := Parse(strings.NewReader(cal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("parsed %d events, want the series and the moved one", len(events))
+	}
+	want := []string{"Mon 2026-01-05 18:00 +0100", "Wed 2026-01-07 18:00 +0100"}
+	if got := occurrences(events[0], 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("series: got %q, want %q", got, want)
+	}
+	if got := occurrences(events[1], 10); len(got) != 1 || got[0] != "Tue 2026-01-06 19:00 +0100" {
+		t.Errorf("moved: got %q", got)
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule        string
+		unsupported bool
+	}{
+		{"FREQ=HOURLY", true},
+		{"FREQ=MONTHLY;BYDAY=1MO", true},
+		{"FREQ=DAILY;BYDAY=MO", true},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", true},
+		{"FREQ=MONTHLY;BYSETPOS=1", true},
+		{"FREQ=FORTNIGHTLY", false},
+		{"FREQ=DAILY;INTERVAL=0", false},
+		{"FREQ=DAILY;COUNT=x", false},
+		{"FREQ=MONTHLY;BYMONTHDAY=x", false},
+	}
+	for _, tt := range tests {
+		_, err := ParseRule(tt.rule)
+		if err == nil {
+			t.Errorf("%s: no error", tt.rule)
+		} else if errors.Is(err, ErrUnsupported) != tt.unsupported {
+			t.Errorf("%s: %v, unsupported %v, want %v", tt.rule, err, !tt.unsupported, tt.unsupported)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned for valid rules this package can't expand,
+// e.g. "BYDAY=1MO" or BYSETPOS
+var ErrUnsupported = errors.New("unsupported")
+
+// Stop expanding a rule after this many periods so an open-ended series
+// that started long ago still ends
+const maxPeriods = 100000
+
+// Rule is an RRULE. Daily, weekly, monthly and yearly rules are supported
+// with INTERVAL, COUNT, UNTIL, weekly BYDAY and monthly BYMONTHDAY.
+type Rule struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY or YEARLY
+	Interval   int
+	Count      int       // Zero means no limit
+	Until      time.Time // Zero means no limit
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRule reads an RRULE value, e.g. "FREQ=WEEKLY;BYDAY=MO,WE,FR"
+func ParseRule(value string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+			if err == nil && r.Interval < 1 {
+				err = fmt.Errorf("invalid INTERVAL %q", val)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			var allDay bool
+			r.Until, allDay, err = parseTime(val, nil)
+			if allDay {
+				// The whole last day is included
+				r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(strings.ToUpper(val), ",") {
+				day, ok := weekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("%w BYDAY %q", ErrUnsupported, d)
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", d)
+				}
+				if n < 1 || n > 31 {
+					// Negative days count from the end of the month
+					return nil, fmt.Errorf("%w BYMONTHDAY %q", ErrUnsupported, d)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "WKST":
+			// Only matters for BYDAY with INTERVAL > 1, where weeks start on
+			// Monday as in most calendars
+		default:
+			return nil, fmt.Errorf("%w rule part %q", ErrUnsupported, part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "SECONDLY", "MINUTELY", "HOURLY":
+		return nil, fmt.Errorf("%w FREQ %q", ErrUnsupported, r.Freq)
+	default:
+		return nil, fmt.Errorf("invalid FREQ %q", r.Freq)
+	}
+	if len(r.ByDay) > 0 && r.Freq != "WEEKLY" {
+		return nil, fmt.Errorf("%w BYDAY in a %s rule", ErrUnsupported, strings.ToLower(r.Freq))
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != "MONTHLY" {
+		return nil, fmt.Errorf("%w BYMONTHDAY in a %s rule", ErrUnsupported, strings.ToLower(r.Freq))
+	}
+	sort.Ints(r.ByMonthDay)
+	return r, nil
+}
+
+// each calls yield with every occurrence of a series starting at start, in
+// order, until yield returns false or the series ends. Occurrences keep the
+// wall-clock time of start in its zone, across daylight saving changes.
+func (r *Rule) each(start time.Time, yield func(time.Time) bool) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		n++
+		if r.Count > 0 && n > r.Count {
+			return false
+		}
+		return yield(t)
+	}
+
+	y, m, d := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	for period := 0; period < maxPeriods; period++ {
+		step := period * r.Interval
+		switch r.Freq {
+		case "DAILY":
+			if !emit(at(y, m, d+step)) {
+				return
+			}
+
+		case "WEEKLY":
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			// Monday of the start week, then every Interval weeks
+			monday := d - (int(start.Weekday())+6)%7 + 7*step
+			offsets := make([]int, len(days))
+			for i, day := range days {
+				offsets[i] = (int(day) + 6) % 7
+			}
+			sort.Ints(offsets)
+			for _, off := range offsets {
+				if !emit(at(y, m, monday+off)) {
+					return
+				}
+			}
+
+		case "MONTHLY":
+			first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, start.Location())
+			days := r.ByMonthDay
+			if len(days) == 0 {
+				days = []int{d}
+			}
+			for _, day := range days {
+				// Months without that day are skipped
+				t := at(first.Year(), first.Month(), day)
+				if t.Month() != first.Month() {
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+
+		case "YEARLY":
+			t := at(y+step, m, d)
+			if t.Day() != d {
+				continue // February 29th
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
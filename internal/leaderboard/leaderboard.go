@@ -0,0 +1,164 @@
+// Package leaderboard keeps the results of an in-house competition and
+// ranks athletes per workout and overall
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/score"
+)
+
+// Competition is a set of workouts and the athletes' results in them
+type Competition struct {
+	Name string `json:"name,omitempty"`
+
+	// Points for 1st, 2nd, ... place; the most points wins. Without a
+	// table each place counts as that many points and the fewest wins.
+	Points []int `json:"points,omitempty"`
+
+	Workouts []Workout `json:"workouts"`
+	Results  []Result  `json:"results"`
+}
+
+// Workout is one event of the competition
+type Workout struct {
+	Name    string     `json:"name"`
+	Scoring score.Kind `json:"scoring"`
+}
+
+// Result is an athlete's score in a workout
+type Result struct {
+	Workout  string      `json:"workout"`
+	Athlete  string      `json:"athlete"`
+	Score    score.Score `json:"score"`
+	Recorded time.Time   `json:"recorded"`
+}
+
+// DefaultPath returns where the competition is kept
+func DefaultPath() string {
+	return filepath.Join(config.Dir(), "leaderboard.json")
+}
+
+// Load reads a competition. A missing file yields an empty one.
+func Load(path string) (*Competition, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Competition{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Competition
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the competition, replacing the file in one step so a crash
+// never leaves half a leaderboard
+func (c *Competition) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Workout looks up a workout by name, ignoring case
+func (c *Competition) Workout(name string) (Workout, bool) {
+	for _, w := range c.Workouts {
+		if strings.EqualFold(w.Name, name) {
+			return w, true
+		}
+	}
+	return Workout{}, false
+}
+
+// AddWorkout adds an event to the competition
+func (c *Competition) AddWorkout(name string, kind score.Kind) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("a workout needs a name")
+	}
+	if _, ok := c.Workout(name); ok {
+		return fmt.Errorf("workout %q already exists", name)
+	}
+	c.Workouts = append(c.Workouts, Workout{Name: name, Scoring: kind})
+	return nil
+}
+
+// Record sets an athlete's result in a workout, replacing any earlier one.
+// Time workouts also take reps, for athletes stopped by the time cap.
+func (c *Competition) Record(workout, athlete string, sc score.Score, at time.Time) error {
+	w, ok := c.Workout(workout)
+	if !ok {
+		return fmt.Errorf("no workout %q", workout)
+	}
+	athlete = strings.TrimSpace(athlete)
+	if athlete == "" {
+		return fmt.Errorf("a result needs an athlete")
+	}
+	if sc.Kind != w.Scoring && !(w.Scoring == score.Time && sc.Kind == score.Reps) {
+		return fmt.Errorf("%s is scored by %s, not %s", w.Name, w.Scoring, sc.Kind)
+	}
+
+	r := Result{Workout: w.Name, Athlete: athlete, Score: sc, Recorded: at}
+	for i, old := range c.Results {
+		if strings.EqualFold(old.Workout, w.Name) && strings.EqualFold(old.Athlete, athlete) {
+			r.Athlete = old.Athlete
+			c.Results[i] = r
+			return nil
+		}
+	}
+	c.Results = append(c.Results, r)
+	return nil
+}
+
+// ParseResult reads a result for a workout. Time workouts accept "3:45",
+// or "120 reps" for an athlete stopped by the cap.
+func ParseResult(w Workout, text string) (score.Score, error) {
+	if w.Scoring == score.Time {
+		if reps, ok := strings.CutSuffix(strings.TrimSpace(text), "reps"); ok {
+			return score.Parse(score.Reps, reps)
+		}
+	}
+	return score.Parse(w.Scoring, text)
+}
+
+// Athletes returns everyone with a result, sorted by name
+func (c *Competition) Athletes() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, r := range c.Results {
+		key := strings.ToLower(r.Athlete)
+		if !seen[key] {
+			seen[key] = true
+			names = append(names, r.Athlete)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LowestWins reports whether standings go to the fewest points, as when
+// points are places
+func (c *Competition) LowestWins() bool {
+	return len(c.Points) == 0
+}
@@ -0,0 +1,116 @@
+package leaderboard
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+
+	"gymtimer/internal/score"
+)
+
+// Placing is an athlete's place in one workout. Athletes without a result
+// share the place after the last scored one.
+type Placing struct {
+	Athlete string
+	Place   int
+	Score   *score.Score // nil without a result
+	Points  int
+}
+
+// Standing is an athlete's overall position
+type Standing struct {
+	Rank     int
+	Athlete  string
+	Total    int
+	Placings []Placing // One per workout, in the competition's order
+}
+
+// Ranking places every athlete in a workout, best first. Equal results
+// share a place and the next place is skipped (1, 2, 2, 4).
+func (c *Competition) Ranking(workout string) []Placing {
+	byAthlete := make(map[string]score.Score)
+	for _, r := range c.Results {
+		if strings.EqualFold(r.Workout, workout) {
+			byAthlete[strings.ToLower(r.Athlete)] = r.Score
+		}
+	}
+
+	placings := make([]Placing, 0, len(byAthlete))
+	var missing []Placing
+	for _, name := range c.Athletes() {
+		if sc, ok := byAthlete[strings.ToLower(name)]; ok {
+			placings = append(placings, Placing{Athlete: name, Score: &sc})
+		} else {
+			missing = append(missing, Placing{Athlete: name})
+		}
+	}
+
+	sort.SliceStable(placings, func(i, j int) bool {
+		return score.Ahead(*placings[i].Score, *placings[j].Score) > 0
+	})
+	for i := range placings {
+		if i > 0 && score.Ahead(*placings[i].Score, *placings[i-1].Score) == 0 {
+			placings[i].Place = placings[i-1].Place
+		} else {
+			placings[i].Place = i + 1
+		}
+	}
+	for i := range missing {
+		missing[i].Place = len(placings) + 1
+	}
+
+	placings = append(placings, missing...)
+	for i := range placings {
+		placings[i].Points = c.points(placings[i])
+	}
+	return placings
+}
+
+// points awards a placing: its place, or from the points table. Without a
+// result there are no table points.
+func (c *Competition) points(p Placing) int {
+	if c.LowestWins() {
+		return p.Place
+	}
+	if p.Score == nil || p.Place > len(c.Points) {
+		return 0
+	}
+	return c.Points[p.Place-1]
+}
+
+// Standings ranks the athletes over every workout by total points. Equal
+// totals share a rank.
+func (c *Competition) Standings() []Standing {
+	index := make(map[string]int)
+	var standings []Standing
+	for _, name := range c.Athletes() {
+		index[strings.ToLower(name)] = len(standings)
+		standings = append(standings, Standing{Athlete: name})
+	}
+
+	for _, w := range c.Workouts {
+		for _, p := range c.Ranking(w.Name) {
+			s := &standings[index[strings.ToLower(p.Athlete)]]
+			s.Placings = append(s.Placings, p)
+			s.Total += p.Points
+		}
+	}
+
+	better := func(a, b Standing) int {
+		if c.LowestWins() {
+			return cmp.Compare(b.Total, a.Total)
+		}
+		return cmp.Compare(a.Total, b.Total)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return better(standings[i], standings[j]) > 0
+	})
+	for i := range standings {
+		if i > 0 && better(standings[i], standings[i-1]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+	return standings
+}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"gymtimer/internal/events"
+)
+
+// Tick lag histogram buckets in seconds
+var lagBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// Registry collects usage counters from timer events and exposes them in
+// the Prometheus text format
+type Registry struct {
+	mu sync.Mutex
+
+	started       map[string]int
+	finished      map[string]int
+	pauses        int
+	activeSeconds int
+	audioFailures int
+	errors        map[string]int
+
+	lastTick   time.Time
+	lagCounts  []int // Cumulative per bucket, plus +Inf at the end
+	lagSum     float64
+	lagCount   int
+	lastLagSec float64
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{
+		started:   make(map[string]int),
+		finished:  make(map[string]int),
+		errors:    make(map[string]int),
+		lagCounts: make([]int, len(lagBuckets)+1),
+	}
+}
+
+// Send records a timer event
+func (r *Registry) Send(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch e.Kind {
+	case events.KindStart:
+		r.started[e.Mode]++
+		r.lastTick = time.Time{}
+	case events.KindFinish:
+		r.finished[e.Mode]++
+		r.lastTick = time.Time{}
+	case events.KindPause:
+		r.pauses++
+		r.lastTick = time.Time{}
+	case events.KindResume:
+		r.lastTick = time.Time{}
+	case events.KindTick:
+		r.activeSeconds++
+		if !r.lastTick.IsZero() {
+			r.observeLag(e.Time.Sub(r.lastTick) - time.Second)
+		}
+		r.lastTick = e.Time
+	}
+}
+
+// observeLag records how late a tick arrived compared to the one second
+// schedule
+func (r *Registry) observeLag(lag time.Duration) {
+	if lag < 0 {
+		lag = 0
+	}
+	secs := lag.Seconds()
+	r.lastLagSec = secs
+	r.lagSum += secs
+	r.lagCount++
+	for i, le := range lagBuckets {
+		if secs <= le {
+			r.lagCounts[i]++
+		}
+	}
+	r.lagCounts[len(lagBuckets)]++
+}
+
+// AudioFailure counts a sound that failed to play
+func (r *Registry) AudioFailure() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.audioFailures++
+}
+
+// IntegrationError counts a failure in an integration such as "webhook"
+func (r *Registry) IntegrationError(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[name]++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+
+	writeLabeled(cw, "gymtimer_workouts_started_total", "Workouts started, by mode.", "mode", r.started)
+	writeLabeled(cw, "gymtimer_workouts_finished_total", "Workouts run to completion, by mode.", "mode", r.finished)
+	writeCounter(cw, "gymtimer_pauses_total", "Times a running workout was paused.", r.pauses)
+	writeCounter(cw, "gymtimer_active_seconds_total", "Seconds a workout timer has been running.", r.activeSeconds)
+	writeCounter(cw, "gymtimer_audio_failures_total", "Sounds that failed to play.", r.audioFailures)
+	writeLabeled(cw, "gymtimer_integration_errors_total", "Errors from hooks, webhooks, MQTT and OSC.", "integration", r.errors)
+
+	fmt.Fprintf(cw, "# HELP gymtimer_tick_lag_last_seconds Delay of the most recent timer tick.\n")
+	fmt.Fprintf(cw, "# TYPE gymtimer_tick_lag_last_seconds gauge\n")
+	fmt.Fprintf(cw, "gymtimer_tick_lag_last_seconds %g\n", r.lastLagSec)
+
+	fmt.Fprintf(cw, "# HELP gymtimer_tick_lag_seconds Delay of timer ticks beyond the one second schedule.\n")
+	fmt.Fprintf(cw, "# TYPE gymtimer_tick_lag_seconds histogram\n")
+	for i, le := range lagBuckets {
+		fmt.Fprintf(cw, "gymtimer_tick_lag_seconds_bucket{le=\"%g\"} %d\n", le, r.lagCounts[i])
+	}
+	fmt.Fprintf(cw, "gymtimer_tick_lag_seconds_bucket{le=\"+Inf\"} %d\n", r.lagCounts[len(lagBuckets)])
+	fmt.Fprintf(cw, "gymtimer_tick_lag_seconds_sum %g\n", r.lagSum)
+	fmt.Fprintf(cw, "gymtimer_tick_lag_seconds_count %d\n", r.lagCount)
+
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the metrics page
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// Listen binds addr and serves /metrics in the background until the
+// process exits
+func (r *Registry) Listen(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	go http.Serve(ln, mux)
+	return nil
+}
+
+func writeCounter(w io.Writer, name, help string, value int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+}
+
+func writeLabeled(w io.Writer, name, help, label string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, label, k, values[k])
+	}
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,270 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	queueSize      = 64
+	connectTimeout = 5 * time.Second
+	publishTimeout = 5 * time.Second
+
+	defaultEventTopic   = "gymtimer/event"
+	defaultStateTopic   = "gymtimer/state"
+	defaultCommandTopic = "gymtimer/command"
+)
+
+// Client is the subset of an MQTT client used by the bridge, so an
+// in-process stand-in can replace a real broker connection
+type Client interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+	Subscribe(topic string, qos byte, handler func(payload []byte)) error
+	Disconnect()
+
+	// OnReconnect registers fn to run each time the connection is
+	// re-established. The broker forgets a clean session's subscriptions
+	// when the connection drops, so they have to be made again.
+	OnReconnect(fn func())
+}
+
+// Handler receives remote commands, e.g. ("mode", "tabata")
+type Handler func(action, arg string)
+
+// Bridge publishes timer events to MQTT and forwards commands from the
+// command topic to a handler
+type Bridge struct {
+	cfg    config.MQTT
+	client Client
+	queue  chan events.Event
+	done   chan struct{}
+
+	// Last value published to each retained state topic, only touched by
+	// the publishing goroutine
+	retained map[string]string
+
+	// OnError is called from the publishing goroutine when a publish fails
+	OnError func(err error)
+}
+
+// Connect dials the configured broker
+func Connect(cfg config.MQTT) (Client, error) {
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = "gymtimer-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(clientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectTimeout(connectTimeout)
+
+	p := &pahoClient{}
+	opts.SetOnConnectHandler(func(paho.Client) { p.connected() })
+
+	p.c = paho.NewClient(opts)
+	token := p.c.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return nil, fmt.Errorf("connecting to %s: timed out", cfg.Broker)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", cfg.Broker, err)
+	}
+	return p, nil
+}
+
+// New creates a bridge on an established client. Commands are delivered to
+// handle, which may be nil to disable remote control.
+func New(cfg config.MQTT, client Client, handle Handler) (*Bridge, error) {
+	if cfg.EventTopic == "" {
+		cfg.EventTopic = defaultEventTopic
+	}
+	if cfg.StateTopic == "" {
+		cfg.StateTopic = defaultStateTopic
+	}
+	if cfg.CommandTopic == "" {
+		cfg.CommandTopic = defaultCommandTopic
+	}
+
+	b := &Bridge{
+		cfg:      cfg,
+		client:   client,
+		queue:    make(chan events.Event, queueSize),
+		done:     make(chan struct{}),
+		retained: make(map[string]string),
+	}
+
+	if handle != nil {
+		subscribe := func() error {
+			err := client.Subscribe(cfg.CommandTopic, cfg.QoS, func(payload []byte) {
+				if action, arg, ok := ParseCommand(payload); ok {
+					handle(action, arg)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("subscribing to %s: %w", cfg.CommandTopic, err)
+			}
+			return nil
+		}
+		if err := subscribe(); err != nil {
+			return nil, err
+		}
+		client.OnReconnect(func() {
+			if err := subscribe(); err != nil {
+				b.fail(fmt.Errorf("mqtt: %w", err))
+			}
+		})
+	}
+
+	go b.publishLoop()
+	return b, nil
+}
+
+// Send queues the event for publishing, dropping it if the queue is full
+func (b *Bridge) Send(e events.Event) {
+	select {
+	case b.queue <- e:
+	default:
+		b.fail(fmt.Errorf("mqtt: queue full, dropped %s event", e.Kind))
+	}
+}
+
+// Close flushes queued events and disconnects from the broker
+func (b *Bridge) Close() {
+	close(b.queue)
+	<-b.done
+	b.client.Disconnect()
+}
+
+func (b *Bridge) publishLoop() {
+	defer close(b.done)
+	for e := range b.queue {
+		if err := b.publish(e); err != nil {
+			b.fail(fmt.Errorf("mqtt: %w", err))
+		}
+	}
+}
+
+// publish sends the event to the event topic and updates the retained state
+// topics: <state> holds the full JSON and <state>/<field> the plain values.
+// A state topic is only published when its value changes, so a tick
+// usually updates just the JSON and the remaining time.
+func (b *Bridge) publish(e events.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if events.Matches(b.cfg.On, e.Kind) {
+		if err := b.client.Publish(b.cfg.EventTopic, b.cfg.QoS, false, payload); err != nil {
+			return err
+		}
+	}
+
+	state := map[string]string{
+		"":           string(payload),
+		"/mode":      e.Mode,
+		"/phase":     e.Phase,
+		"/round":     strconv.Itoa(e.Round),
+		"/remaining": strconv.Itoa(e.Remaining),
+		"/finished":  strconv.FormatBool(e.Kind == events.KindFinish),
+	}
+	for suffix, value := range state {
+		topic := b.cfg.StateTopic + suffix
+		if last, ok := b.retained[topic]; ok && last == value {
+			continue
+		}
+		if err := b.client.Publish(topic, b.cfg.QoS, true, []byte(value)); err != nil {
+			return err
+		}
+		b.retained[topic] = value
+	}
+	return nil
+}
+
+func (b *Bridge) fail(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
+
+// ParseCommand reads a command payload. Both plain text ("start",
+// "mode tabata") and JSON ({"action":"mode","arg":"tabata"}) are accepted.
+func ParseCommand(payload []byte) (action, arg string, ok bool) {
+	text := strings.TrimSpace(string(payload))
+	if strings.HasPrefix(text, "{") {
+		var cmd struct {
+			Action string `json:"action"`
+			Arg    string `json:"arg"`
+		}
+		if err := json.Unmarshal(payload, &cmd); err != nil {
+			return "", "", false
+		}
+		action, arg = cmd.Action, cmd.Arg
+	} else {
+		action, arg, _ = strings.Cut(text, " ")
+	}
+
+	action = strings.ToLower(strings.TrimSpace(action))
+	arg = strings.TrimSpace(arg)
+	return action, arg, action != ""
+}
+
+// pahoClient adapts the Paho client to Client
+type pahoClient struct {
+	c paho.Client
+
+	mu          sync.Mutex
+	onReconnect []func()
+}
+
+func (p *pahoClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	token := p.c.Publish(topic, qos, retained, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("publishing to %s: timed out", topic)
+	}
+	return token.Error()
+}
+
+func (p *pahoClient) Subscribe(topic string, qos byte, handler func(payload []byte)) error {
+	token := p.c.Subscribe(topic, qos, func(_ paho.Client, msg paho.Message) {
+		handler(msg.Payload())
+	})
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("subscribing to %s: timed out", topic)
+	}
+	return token.Error()
+}
+
+func (p *pahoClient) Disconnect() {
+	p.c.Disconnect(250)
+}
+
+func (p *pahoClient) OnReconnect(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReconnect = append(p.onReconnect, fn)
+}
+
+// connected runs on every connection, including the first. Repeating a
+// subscription that is still in place is harmless.
+func (p *pahoClient) connected() {
+	p.mu.Lock()
+	fns := append([]func(){}, p.onReconnect...)
+	p.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
@@ -0,0 +1,268 @@
+package mqtt
+
+import (
+	"sync"
+	"testing"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+)
+
+// message is one publish seen by the fake client
+type message struct {
+	topic    string
+	retained bool
+	payload  string
+}
+
+// fakeClient stands in for a broker connection, recording publishes and
+// holding the command handler so tests can deliver commands
+type fakeClient struct {
+	mu           sync.Mutex
+	published    []message
+	subscribed   string
+	handler      func(payload []byte)
+	onReconnect  []func()
+	disconnected bool
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published = append(c.published, message{topic, retained, string(payload)})
+	return nil
+}
+
+func (c *fakeClient) Subscribe(topic string, qos byte, handler func(payload []byte)) error {
+	c.subscribed, c.handler = topic, handler
+	return nil
+}
+
+func (c *fakeClient) Disconnect() {
+	c.disconnected = true
+}
+
+func (c *fakeClient) OnReconnect(fn func()) {
+	c.onReconnect = append(c.onReconnect, fn)
+}
+
+// reconnect drops the connection the way a clean session does, losing the
+// subscription, and connects again
+func (c *fakeClient) reconnect() {
+	c.subscribed, c.handler = "", nil
+	for _, fn := range c.onReconnect {
+		fn()
+	}
+}
+
+// on returns the payloads published to topic, in order
+func (c *fakeClient) on(topic string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var payloads []string
+	for _, m := range c.published {
+		if m.topic == topic {
+			payloads = append(payloads, m.payload)
+		}
+	}
+	return payloads
+}
+
+// retained returns the last value of each retained topic
+func (c *fakeClient) retained() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := make(map[string]string)
+	for _, m := range c.published {
+		if m.retained {
+			last[m.topic] = m.payload
+		}
+	}
+	return last
+}
+
+// run sends the events through a bridge and closes it, which flushes the
+// queue
+func run(t *testing.T, cfg config.MQTT, es ...events.Event) *fakeClient {
+	t.Helper()
+	c := &fakeClient{}
+	b, err := New(cfg, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range es {
+		b.Send(e)
+	}
+	b.Close()
+	if !c.disconnected {
+		t.Error("Close did not disconnect")
+	}
+	return c
+}
+
+func TestStateTopics(t *testing.T) {
+	c := run(t, config.MQTT{},
+		events.Event{Kind: events.KindStart, Mode: "tabata", Phase: "work", Round: 1, Remaining: 20},
+		events.Event{Kind: events.KindTick, Mode: "tabata", Phase: "rest", Round: 1, Remaining: 10},
+		events.Event{Kind: events.KindFinish, Mode: "tabata", Phase: "work", Round: 8, Remaining: 0},
+	)
+
+	want := map[string]string{
+		"gymtimer/state/mode":      "tabata",
+		"gymtimer/state/phase":     "work",
+		"gymtimer/state/round":     "8",
+		"gymtimer/state/remaining": "0",
+		"gymtimer/state/finished":  "true",
+	}
+	got := c.retained()
+	for topic, value := range want {
+		if got[topic] != value {
+			t.Errorf("%s = %q, want %q", topic, got[topic], value)
+		}
+	}
+	if got["gymtimer/state"] == "" {
+		t.Error("no full state published")
+	}
+	if phases := c.on("gymtimer/state/phase"); len(phases) != 3 {
+		t.Errorf("phase published %v, want work, rest, work", phases)
+	}
+}
+
+func TestStateOnlyWhenChanged(t *testing.T) {
+	var ticks []events.Event
+	for remaining := 20; remaining > 15; remaining-- {
+		ticks = append(ticks, events.Event{Kind: events.KindTick, Mode: "tabata", Phase: "work", Round: 1, Remaining: remaining})
+	}
+	c := run(t, config.MQTT{}, ticks...)
+
+	for _, topic := range []string{"gymtimer/state/mode", "gymtimer/state/phase", "gymtimer/state/round", "gymtimer/state/finished"} {
+		if n := len(c.on(topic)); n != 1 {
+			t.Errorf("%s published %d times, want once", topic, n)
+		}
+	}
+	if n := len(c.on("gymtimer/state/remaining")); n != len(ticks) {
+		t.Errorf("remaining published %d times, want %d", n, len(ticks))
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	c := run(t, config.MQTT{EventTopic: "gym/events", On: []string{"start", "finish"}},
+		events.Event{Kind: events.KindStart},
+		events.Event{Kind: events.KindTick, Remaining: 5},
+		events.Event{Kind: events.KindPause},
+		events.Event{Kind: events.KindFinish},
+	)
+
+	if got := c.on("gym/events"); len(got) != 2 {
+		t.Errorf("published %d events, want start and finish: %v", len(got), got)
+	}
+	// State follows every event, filtered or not
+	if got := c.retained()["gymtimer/state/remaining"]; got != "0" {
+		t.Errorf("remaining = %q, want 0", got)
+	}
+}
+
+func TestEventsNotRetained(t *testing.T) {
+	c := run(t, config.MQTT{}, events.Event{Kind: events.KindStart})
+	for _, m := range c.published {
+		if m.topic == "gymtimer/event" && m.retained {
+			t.Error("event published retained")
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	type command struct{ action, arg string }
+	var got []command
+
+	c := &fakeClient{}
+	b, err := New(config.MQTT{CommandTopic: "gym/cmd"}, c, func(action, arg string) {
+		got = append(got, command{action, arg})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if c.subscribed != "gym/cmd" {
+		t.Fatalf("subscribed to %q, want gym/cmd", c.subscribed)
+	}
+
+	for _, payload := range []string{
+		"start",
+		"  Mode tabata ",
+		`{"action":"mode","arg":"for time"}`,
+		"",
+		`{"action":`,
+		`{"arg":"tabata"}`,
+	} {
+		c.handler([]byte(payload))
+	}
+
+	want := []command{{"start", ""}, {"mode", "tabata"}, {"mode", "for time"}}
+	if len(got) != len(want) {
+		t.Fatalf("got commands %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResubscribeOnReconnect(t *testing.T) {
+	var got []string
+	c := &fakeClient{}
+	b, err := New(config.MQTT{CommandTopic: "gym/cmd"}, c, func(action, arg string) {
+		got = append(got, action)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	c.handler([]byte("start"))
+	c.reconnect()
+	if c.subscribed != "gym/cmd" {
+		t.Fatalf("subscribed to %q after reconnecting, want gym/cmd", c.subscribed)
+	}
+	c.handler([]byte("pause"))
+
+	if len(got) != 2 || got[0] != "start" || got[1] != "pause" {
+		t.Errorf("got commands %v, want start and pause", got)
+	}
+}
+
+func TestNoSubscribeWithoutHandler(t *testing.T) {
+	c := &fakeClient{}
+	b, err := New(config.MQTT{}, c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.reconnect()
+	b.Close()
+	if c.subscribed != "" {
+		t.Errorf("subscribed to %q without a handler", c.subscribed)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		payload     string
+		action, arg string
+		ok          bool
+	}{
+		{"pause", "pause", "", true},
+		{"RESET\n", "reset", "", true},
+		{"mode death by", "mode", "death by", true},
+		{`{"action":"Start"}`, "start", "", true},
+		{` {"action":"mode","arg":" emom "}`, "mode", "emom", true},
+		{"   ", "", "", false},
+		{"{not json", "", "", false},
+	}
+	for _, tt := range tests {
+		action, arg, ok := ParseCommand([]byte(tt.payload))
+		if action != tt.action || arg != tt.arg || ok != tt.ok {
+			t.Errorf("ParseCommand(%q) = %q, %q, %v, want %q, %q, %v", tt.payload, action, arg, ok, tt.action, tt.arg, tt.ok)
+		}
+	}
+}
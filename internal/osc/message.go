@@ -0,0 +1,165 @@
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Message is a single OSC message. Args may hold int32, float32, string and
+// bool values, and nil for the N (nil) tag.
+type Message struct {
+	Address string
+	Args    []any
+}
+
+// MarshalBinary encodes the message in OSC 1.0 wire format
+func (m Message) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	writeString(&buf, m.Address)
+
+	tags := ","
+	var args bytes.Buffer
+	for _, a := range m.Args {
+		switch v := a.(type) {
+		case int32:
+			tags += "i"
+			binary.Write(&args, binary.BigEndian, v)
+		case int:
+			tags += "i"
+			binary.Write(&args, binary.BigEndian, int32(v))
+		case float32:
+			tags += "f"
+			binary.Write(&args, binary.BigEndian, math.Float32bits(v))
+		case string:
+			tags += "s"
+			writeString(&args, v)
+		case bool:
+			if v {
+				tags += "T"
+			} else {
+				tags += "F"
+			}
+		case nil:
+			tags += "N"
+		default:
+			return nil, fmt.Errorf("osc: unsupported argument type %T", a)
+		}
+	}
+	writeString(&buf, tags)
+	buf.Write(args.Bytes())
+	return buf.Bytes(), nil
+}
+
+// Parse decodes a packet into its messages, flattening bundles
+func Parse(packet []byte) ([]Message, error) {
+	if bytes.HasPrefix(packet, []byte("#bundle\x00")) {
+		return parseBundle(packet)
+	}
+	m, err := parseMessage(packet)
+	if err != nil {
+		return nil, err
+	}
+	return []Message{m}, nil
+}
+
+func parseBundle(packet []byte) ([]Message, error) {
+	// "#bundle\0" followed by an 8 byte time tag, then size-prefixed elements
+	if len(packet) < 16 {
+		return nil, errors.New("osc: truncated bundle header")
+	}
+	rest := packet[16:]
+	var msgs []Message
+	for len(rest) > 0 {
+		if len(rest) < 4 {
+			return nil, errors.New("osc: truncated bundle")
+		}
+		size := int(binary.BigEndian.Uint32(rest))
+		rest = rest[4:]
+		if size > len(rest) {
+			return nil, errors.New("osc: truncated bundle element")
+		}
+		inner, err := Parse(rest[:size])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, inner...)
+		rest = rest[size:]
+	}
+	return msgs, nil
+}
+
+func parseMessage(packet []byte) (Message, error) {
+	address, rest, err := readString(packet)
+	if err != nil {
+		return Message{}, err
+	}
+	if !strings.HasPrefix(address, "/") {
+		return Message{}, fmt.Errorf("osc: invalid address %q", address)
+	}
+	m := Message{Address: address}
+
+	// Type tags are optional in very old implementations
+	if len(rest) == 0 {
+		return m, nil
+	}
+	tags, rest, err := readString(rest)
+	if err != nil {
+		return Message{}, err
+	}
+
+	for _, tag := range strings.TrimPrefix(tags, ",") {
+		switch tag {
+		case 'i':
+			if len(rest) < 4 {
+				return Message{}, errors.New("osc: truncated int argument")
+			}
+			m.Args = append(m.Args, int32(binary.BigEndian.Uint32(rest)))
+			rest = rest[4:]
+		case 'f':
+			if len(rest) < 4 {
+				return Message{}, errors.New("osc: truncated float argument")
+			}
+			m.Args = append(m.Args, math.Float32frombits(binary.BigEndian.Uint32(rest)))
+			rest = rest[4:]
+		case 's':
+			var s string
+			s, rest, err = readString(rest)
+			if err != nil {
+				return Message{}, err
+			}
+			m.Args = append(m.Args, s)
+		case 'T':
+			m.Args = append(m.Args, true)
+		case 'F':
+			m.Args = append(m.Args, false)
+		case 'N':
+			m.Args = append(m.Args, nil)
+		default:
+			return Message{}, fmt.Errorf("osc: unsupported type tag %q", tag)
+		}
+	}
+	return m, nil
+}
+
+// writeString writes a null-terminated string padded to 4 bytes
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	pad := 4 - len(s)%4
+	buf.Write(make([]byte, pad))
+}
+
+func readString(b []byte) (string, []byte, error) {
+	end := bytes.IndexByte(b, 0)
+	if end < 0 {
+		return "", nil, errors.New("osc: unterminated string")
+	}
+	size := (end/4 + 1) * 4
+	if size > len(b) {
+		return "", nil, errors.New("osc: truncated string padding")
+	}
+	return string(b[:end]), b[size:], nil
+}
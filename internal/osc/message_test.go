@@ -0,0 +1,163 @@
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// bundle wraps the packets in a #bundle with an immediate time tag
+func bundle(packets ...[]byte) []byte {
+	buf := bytes.NewBufferString("#bundle\x00")
+	binary.Write(buf, binary.BigEndian, uint64(1))
+	for _, p := range packets {
+		binary.Write(buf, binary.BigEndian, uint32(len(p)))
+		buf.Write(p)
+	}
+	return buf.Bytes()
+}
+
+func marshal(t *testing.T, m Message) []byte {
+	t.Helper()
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []Message{
+		{Address: "/gymtimer/start"},
+		{Address: "/gymtimer/mode", Args: []any{"tabata"}},
+		{Address: "/gymtimer/tick", Args: []any{"emom", "work", int32(3), int32(10), int32(42), int32(0)}},
+		{Address: "/fader", Args: []any{float32(0.75)}},
+		{Address: "/button", Args: []any{true, false, nil}},
+		{Address: "/mixed", Args: []any{"abc", true, int32(-7), nil, float32(-1.5), "", false}},
+	}
+	for _, want := range tests {
+		msgs, err := Parse(marshal(t, want))
+		if err != nil {
+			t.Errorf("%s: %v", want.Address, err)
+			continue
+		}
+		if len(msgs) != 1 || !reflect.DeepEqual(msgs[0], want) {
+			t.Errorf("%s: got %#v, want %#v", want.Address, msgs, want)
+		}
+	}
+}
+
+func TestMarshalInt(t *testing.T) {
+	msgs, err := Parse(marshal(t, Message{Address: "/n", Args: []any{5}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msgs[0].Args[0]; got != int32(5) {
+		t.Errorf("int argument decoded as %#v, want int32(5)", got)
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	if _, err := (Message{Address: "/x", Args: []any{1.5}}).MarshalBinary(); err == nil {
+		t.Error("marshaled a float64 argument")
+	}
+}
+
+func TestPadding(t *testing.T) {
+	tests := []struct {
+		s    string
+		size int
+	}{
+		{"", 4},
+		{"abc", 4},
+		{"abcd", 8},
+		{"abcdefg", 8},
+		{"abcdefgh", 12},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writeString(&buf, tt.s)
+		if buf.Len() != tt.size {
+			t.Errorf("%q padded to %d bytes, want %d", tt.s, buf.Len(), tt.size)
+		}
+		got, rest, err := readString(append(buf.Bytes(), 0xAA))
+		if err != nil || got != tt.s || !bytes.Equal(rest, []byte{0xAA}) {
+			t.Errorf("readString(%q) = %q, % x, %v", tt.s, got, rest, err)
+		}
+	}
+
+	b := marshal(t, Message{Address: "/abc", Args: []any{"x", int32(1)}})
+	if len(b)%4 != 0 {
+		t.Errorf("message of %d bytes is not 4-byte aligned", len(b))
+	}
+}
+
+func TestBundle(t *testing.T) {
+	start := marshal(t, Message{Address: "/gymtimer/start"})
+	mode := marshal(t, Message{Address: "/gymtimer/mode", Args: []any{"emom"}})
+	pause := marshal(t, Message{Address: "/gymtimer/pause", Args: []any{true}})
+
+	msgs, err := Parse(bundle(start, bundle(mode, bundle(pause))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range msgs {
+		got = append(got, m.Address)
+	}
+	want := []string{"/gymtimer/start", "/gymtimer/mode", "/gymtimer/pause"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if msgs[1].Args[0] != "emom" || msgs[2].Args[0] != true {
+		t.Errorf("arguments lost in the bundle: %v", msgs)
+	}
+
+	if msgs, err := Parse(bundle()); err != nil || len(msgs) != 0 {
+		t.Errorf("empty bundle = %v, %v", msgs, err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	valid := marshal(t, Message{Address: "/a", Args: []any{int32(1)}})
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{"empty", nil},
+		{"no address terminator", []byte("/abc")},
+		{"address without slash", []byte("abc\x00")},
+		{"string padding cut", []byte("/abcd\x00")},
+		{"tags unterminated", []byte("/a\x00\x00,iii")},
+		{"int cut", []byte("/a\x00\x00,i\x00\x00\x00\x00\x01")},
+		{"float cut", []byte("/a\x00\x00,f\x00\x00")},
+		{"string argument unterminated", []byte("/a\x00\x00,s\x00\x00abcd")},
+		{"unknown tag", []byte("/a\x00\x00,b\x00\x00\x00\x00\x00\x04abcd")},
+		{"bundle header cut", []byte("#bundle\x00\x00\x00")},
+		{"bundle size cut", append(bundle(), 0, 0)},
+		{"bundle element too long", append(bundle(), 0, 0, 1, 0, '/', 'a', 0, 0)},
+		{"bundle element empty", append(bundle(), 0, 0, 0, 0)},
+		{"bad message in bundle", bundle(valid, []byte("oops\x00\x00\x00\x00"))},
+		{"bad nested bundle", bundle(bundle(valid[:len(valid)-1]))},
+	}
+	for _, tt := range tests {
+		if msgs, err := Parse(tt.packet); err == nil {
+			t.Errorf("%s: parsed as %v, want an error", tt.name, msgs)
+		}
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	// Every prefix must fail cleanly or parse, never panic
+	full := bundle(
+		marshal(t, Message{Address: "/gymtimer/mode", Args: []any{"for time", int32(3), float32(2.5), true, nil}}),
+		bundle(marshal(t, Message{Address: "/gymtimer/start"})),
+	)
+	for n := range len(full) {
+		Parse(full[:n])
+	}
+	if _, err := Parse(full[:len(full)-1]); err == nil {
+		t.Error("parsed a bundle missing its last byte")
+	}
+}
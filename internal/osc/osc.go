@@ -0,0 +1,170 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+)
+
+const defaultPrefix = "/gymtimer"
+
+// Handler receives remote commands, e.g. ("mode", "tabata")
+type Handler func(action, arg string)
+
+// Bridge sends OSC messages for timer events and accepts OSC commands.
+//
+// Outgoing messages use the address <prefix>/<event> with the arguments
+// mode, phase, round, total rounds, seconds remaining and countdown.
+// Incoming commands are <prefix>/start, /pause, /toggle, /reset and
+// /mode <name>.
+type Bridge struct {
+	cfg     config.OSC
+	targets []*net.UDPConn
+	listen  *net.UDPConn
+	handle  Handler
+
+	// OnError is called when a message cannot be sent or parsed
+	OnError func(err error)
+}
+
+// New resolves the targets and, when a listen address is configured, starts
+// receiving commands for handle
+func New(cfg config.OSC, handle Handler) (*Bridge, error) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultPrefix
+	}
+	cfg.Prefix = "/" + strings.Trim(cfg.Prefix, "/")
+
+	b := &Bridge{cfg: cfg, handle: handle}
+	for _, target := range cfg.Targets {
+		addr, err := net.ResolveUDPAddr("udp", target)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("resolving %s: %w", target, err)
+		}
+		conn, err := net.DialUDP("udp", nil, addr)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("dialing %s: %w", target, err)
+		}
+		b.targets = append(b.targets, conn)
+	}
+
+	if cfg.Listen != "" && handle != nil {
+		addr, err := net.ResolveUDPAddr("udp", cfg.Listen)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("resolving %s: %w", cfg.Listen, err)
+		}
+		b.listen, err = net.ListenUDP("udp", addr)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("listening on %s: %w", cfg.Listen, err)
+		}
+		go b.serve()
+	}
+
+	return b, nil
+}
+
+// Addr returns the address commands are received on, or nil
+func (b *Bridge) Addr() net.Addr {
+	if b.listen == nil {
+		return nil
+	}
+	return b.listen.LocalAddr()
+}
+
+// Send writes the event to every target. UDP writes do not wait for the
+// receiver, so this never stalls the caller.
+func (b *Bridge) Send(e events.Event) {
+	if !events.Matches(b.cfg.On, e.Kind) {
+		return
+	}
+
+	msg := Message{
+		Address: b.cfg.Prefix + "/" + string(e.Kind),
+		Args:    []any{e.Mode, e.Phase, e.Round, e.TotalRounds, e.Remaining, e.Countdown},
+	}
+	packet, err := msg.MarshalBinary()
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	for _, conn := range b.targets {
+		if _, err := conn.Write(packet); err != nil {
+			b.fail(fmt.Errorf("osc: sending to %s: %w", conn.RemoteAddr(), err))
+		}
+	}
+}
+
+// Close stops listening and releases the target sockets
+func (b *Bridge) Close() {
+	if b.listen != nil {
+		b.listen.Close()
+	}
+	for _, conn := range b.targets {
+		conn.Close()
+	}
+}
+
+func (b *Bridge) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, _, err := b.listen.ReadFromUDP(buf)
+		if err != nil {
+			// Closed by Close
+			return
+		}
+		msgs, err := Parse(buf[:n])
+		if err != nil {
+			b.fail(err)
+			continue
+		}
+		for _, msg := range msgs {
+			b.dispatch(msg)
+		}
+	}
+}
+
+func (b *Bridge) dispatch(msg Message) {
+	action, ok := strings.CutPrefix(msg.Address, b.cfg.Prefix+"/")
+	if !ok {
+		return
+	}
+
+	var arg string
+	if len(msg.Args) > 0 {
+		switch v := msg.Args[0].(type) {
+		case string:
+			arg = v
+		case int32:
+			// Control surfaces send 1 on press and 0 on release
+			if v == 0 {
+				return
+			}
+		case float32:
+			if v == 0 {
+				return
+			}
+		case bool:
+			if !v {
+				return
+			}
+		}
+	}
+
+	switch action {
+	case "start", "pause", "toggle", "reset", "mode":
+		b.handle(action, arg)
+	}
+}
+
+func (b *Bridge) fail(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
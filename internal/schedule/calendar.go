@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gymtimer/internal/ical"
+	"gymtimer/internal/workout"
+)
+
+// loadCalendar builds a schedule from the timed events of an iCalendar
+// file. Each event's description names its workout, either in notation on
+// a line of its own ("EMOM 12") or as a workout file ("workout:
+// emom12.json", relative to the calendar). Events whose description and
+// summary hold no workout are not classes and are left out.
+func loadCalendar(path string) (*Schedule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events, err := ical.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	s := &Schedule{}
+	for _, e := range events {
+		if e.AllDay {
+			continue
+		}
+		w, ok, err := eventWorkout(e, filepath.Dir(path))
+		if err != nil {
+			return nil, fmt.Errorf("%s: event %q: %w", path, e.Summary, err)
+		}
+		if !ok {
+			continue
+		}
+		// A class that can't run is left out once here, rather than failing
+		// every time it comes up
+		reason := e.Unsupported
+		if reason == nil {
+			_, reason = w.Timer()
+		}
+		if reason != nil {
+			s.Warnings = append(s.Warnings, fmt.Sprintf("%s: skipped event %q: %v", path, e.Summary, reason))
+			continue
+		}
+		s.Calendar = append(s.Calendar, Recurring{Event: e, Workout: w})
+	}
+	return s, nil
+}
+
+// eventWorkout finds the workout of a calendar event
+func eventWorkout(e ical.Event, dir string) (workout.Workout, bool, error) {
+	lines := append(strings.Split(e.Description, "\n"), e.Summary)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "workout") {
+			line = strings.TrimSpace(value)
+		}
+
+		if strings.HasSuffix(strings.ToLower(line), ".json") {
+			if !filepath.IsAbs(line) {
+				line = filepath.Join(dir, line)
+			}
+			w, err := workout.Load(line)
+			if err != nil {
+				return workout.Workout{}, false, err
+			}
+			return w, true, nil
+		}
+
+		if w, err := workout.Parse(line); err == nil {
+			return w, true, nil
+		}
+	}
+	return workout.Workout{}, false, nil
+}
@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gymtimer/internal/ical"
+	"gymtimer/internal/workout"
+)
+
+// Class is one workout on the schedule
+type Class struct {
+	Start   time.Time
+	Name    string
+	Workout workout.Workout
+}
+
+// Slot is a class that takes place every day at the same time
+type Slot struct {
+	Offset  time.Duration // Since midnight
+	Name    string
+	Workout workout.Workout
+}
+
+// Recurring is a class taken from a calendar event, which may repeat
+type Recurring struct {
+	Event   ical.Event
+	Workout workout.Workout
+}
+
+// Schedule is a day plan of daily slots plus calendar events
+type Schedule struct {
+	Daily    []Slot
+	Calendar []Recurring
+
+	// Classes left out of the plan and why, e.g. calendar events that
+	// repeat in a way that can't be followed or have an invalid workout
+	Warnings []string
+}
+
+// file is the on-disk day plan, e.g.
+//
+//	{"classes": [
+//	  {"time": "06:30", "name": "Early bird", "workout": "tabata.json"},
+//	  {"time": "17:30", "workout": "emom12.json"}
+//	]}
+//
+// Workout paths are relative to the schedule file.
+type file struct {
+	Classes []struct {
+		Time    string `json:"time"`
+		Name    string `json:"name"`
+		Workout string `json:"workout"`
+	} `json:"classes"`
+}
+
+// Load reads a day plan, either a JSON file or an iCalendar (.ics) file
+func Load(path string) (*Schedule, error) {
+	if strings.EqualFold(filepath.Ext(path), ".ics") {
+		return loadCalendar(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	s := &Schedule{}
+	for _, c := range f.Classes {
+		offset, err := parseTimeOfDay(c.Time)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		workoutPath := c.Workout
+		if !filepath.IsAbs(workoutPath) {
+			workoutPath = filepath.Join(filepath.Dir(path), workoutPath)
+		}
+		w, err := workout.Load(workoutPath)
+		if err != nil {
+			return nil, fmt.Errorf("class at %s: %w", c.Time, err)
+		}
+		if _, err := w.Timer(); err != nil {
+			return nil, fmt.Errorf("class at %s: %w", c.Time, err)
+		}
+
+		s.Daily = append(s.Daily, Slot{Offset: offset, Name: c.Name, Workout: w})
+	}
+
+	sort.Slice(s.Daily, func(i, j int) bool { return s.Daily[i].Offset < s.Daily[j].Offset })
+	return s, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid class time %q, expected HH:MM or HH:MM:SS", s)
+}
+
+// Next returns the first class starting after now
+func (s *Schedule) Next(now time.Time) (Class, bool) {
+	var next Class
+	found := false
+	consider := func(c Class) {
+		if c.Start.After(now) && (!found || c.Start.Before(next.Start)) {
+			next = c
+			found = true
+		}
+	}
+
+	for _, r := range s.Calendar {
+		if start, ok := r.Event.Next(now); ok {
+			consider(Class{Start: start.In(now.Location()), Name: r.Event.Summary, Workout: r.Workout})
+		}
+	}
+
+	// Daily slots today, or tomorrow once today's have passed
+	for day := 0; day <= 1; day++ {
+		y, m, d := now.AddDate(0, 0, day).Date()
+		for _, slot := range s.Daily {
+			h := int(slot.Offset / time.Hour)
+			min := int(slot.Offset % time.Hour / time.Minute)
+			sec := int(slot.Offset % time.Minute / time.Second)
+			start := time.Date(y, m, d, h, min, sec, 0, now.Location())
+			consider(Class{Start: start, Name: slot.Name, Workout: slot.Workout})
+		}
+	}
+
+	return next, found
+}
+
+// Label describes the class for display, e.g. "17:30 — EMOM 12"
+func (c Class) Label() string {
+	desc := c.Workout.Mode
+	if t, err := c.Workout.Timer(); err == nil {
+		desc = t.Summary()
+	}
+	if c.Name != "" {
+		desc = c.Name + " (" + desc + ")"
+	}
+	return c.Start.Format("15:04") + " — " + desc
+}
@@ -0,0 +1,165 @@
+// Package score holds workout results and how they compare
+package score
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is how a workout is scored
+type Kind string
+
+const (
+	Time       Kind = "time"        // For Time: lower is better
+	Reps       Kind = "reps"        // Total reps
+	RoundsReps Kind = "rounds+reps" // AMRAP: full rounds plus extra reps
+	Load       Kind = "load"        // Heaviest weight lifted
+)
+
+// Score is one result
+type Score struct {
+	Kind   Kind          `json:"kind"`
+	Time   time.Duration `json:"time,omitempty"`
+	Rounds int           `json:"rounds,omitempty"`
+	Reps   int           `json:"reps,omitempty"`
+	Load   float64       `json:"load,omitempty"`
+}
+
+// ParseKind looks up a scoring kind by name
+func ParseKind(name string) (Kind, error) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(name))); k {
+	case Time, Reps, RoundsReps, Load:
+		return k, nil
+	case "rounds":
+		return RoundsReps, nil
+	default:
+		return "", fmt.Errorf("unknown scoring %q, expected time, reps, rounds+reps or load", name)
+	}
+}
+
+// Parse reads a result as an athlete would write it: "3:45" for time,
+// "150" for reps, "5+12" or "5" for rounds and reps, "102.5" for load
+func Parse(kind Kind, text string) (Score, error) {
+	text = strings.TrimSpace(text)
+	s := Score{Kind: kind}
+	var err error
+
+	switch kind {
+	case Time:
+		s.Time, err = parseClock(text)
+	case Reps:
+		s.Reps, err = parseCount(text)
+	case RoundsReps:
+		rounds, reps, _ := strings.Cut(text, "+")
+		if s.Rounds, err = parseCount(rounds); err == nil && reps != "" {
+			s.Reps, err = parseCount(reps)
+		}
+	case Load:
+		s.Load, err = strconv.ParseFloat(text, 64)
+		if err == nil && s.Load < 0 {
+			err = fmt.Errorf("negative load")
+		}
+	default:
+		return Score{}, fmt.Errorf("unknown scoring %q", kind)
+	}
+	if err != nil {
+		return Score{}, fmt.Errorf("invalid %s score %q", kind, text)
+	}
+	return s, nil
+}
+
+func parseCount(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	return n, nil
+}
+
+// parseClock reads "M:SS", "H:MM:SS" or a Go duration such as "225s"
+func parseClock(s string) (time.Duration, error) {
+	if !strings.Contains(s, ":") {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		return d, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	var d time.Duration
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		// Only the leading field may run past 59
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return d * time.Second, nil
+}
+
+// Compare returns a positive number if s is better than o, negative if it
+// is worse and zero for a tie
+func (s Score) Compare(o Score) int {
+	switch s.Kind {
+	case Time:
+		return cmp.Compare(o.Time, s.Time)
+	case Reps:
+		return cmp.Compare(s.Reps, o.Reps)
+	case RoundsReps:
+		if c := cmp.Compare(s.Rounds, o.Rounds); c != 0 {
+			return c
+		}
+		return cmp.Compare(s.Reps, o.Reps)
+	case Load:
+		return cmp.Compare(s.Load, o.Load)
+	default:
+		return 0
+	}
+}
+
+// Ahead compares two results of the same workout: positive if a places
+// ahead of b. A finish time beats any score logged at the time cap.
+func Ahead(a, b Score) int {
+	switch {
+	case (a.Kind == Time) != (b.Kind == Time):
+		if a.Kind == Time {
+			return 1
+		}
+		return -1
+	case a.Kind != b.Kind:
+		return 0
+	default:
+		return a.Compare(b)
+	}
+}
+
+// String formats the score for display, e.g. "3:45" or "5+12"
+func (s Score) String() string {
+	switch s.Kind {
+	case Time:
+		secs := int(s.Time.Round(time.Second).Seconds())
+		if secs >= 3600 {
+			return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+		}
+		return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+	case Reps:
+		return fmt.Sprintf("%d reps", s.Reps)
+	case RoundsReps:
+		return fmt.Sprintf("%d+%d", s.Rounds, s.Reps)
+	case Load:
+		return strconv.FormatFloat(s.Load, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
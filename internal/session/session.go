@@ -0,0 +1,111 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/timer"
+)
+
+// Snapshot is the saved state of an in-progress session
+type Snapshot struct {
+	SavedAt   time.Time        `json:"saved_at"`
+	Timer     *timer.Timer     `json:"timer"`
+	Stopwatch *timer.Stopwatch `json:"stopwatch"`
+	Started   bool             `json:"started"` // A workout was under way
+}
+
+// DefaultPath returns where the session snapshot is kept
+func DefaultPath() string {
+	return filepath.Join(config.Dir(), "session.json")
+}
+
+// New captures the current state
+func New(t *timer.Timer, sw *timer.Stopwatch, started bool) *Snapshot {
+	tc := *t
+	swc := *sw
+	return &Snapshot{
+		SavedAt:   time.Now(),
+		Timer:     &tc,
+		Stopwatch: &swc,
+		Started:   started,
+	}
+}
+
+// Save writes the snapshot atomically so a crash never leaves a torn file
+func Save(path string, s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads a snapshot. It returns nil without error if there is none.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Timer == nil || s.Stopwatch == nil {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// Clear removes the snapshot
+func Clear(path string) error {
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Missed returns the whole seconds of wall time since the snapshot was saved
+func (s *Snapshot) Missed(now time.Time) int {
+	missed := int(now.Sub(s.SavedAt) / time.Second)
+	if missed < 0 {
+		return 0
+	}
+	return missed
+}
+
+// CatchUp advances whatever was running by the wall time that passed since
+// the snapshot was saved, as if the session had never been interrupted
+func (s *Snapshot) CatchUp(now time.Time) {
+	missed := s.Missed(now)
+
+	if s.Stopwatch.Running {
+		s.Stopwatch.Elapsed += time.Duration(missed) * time.Second
+	}
+
+	switch s.Timer.Mode {
+	case timer.ModeClock:
+	case timer.ModeStopwatch:
+		// Only the stopwatch counts in stopwatch mode
+	default:
+		for i := 0; i < missed && s.Timer.Running; i++ {
+			s.Timer.Advance()
+		}
+	}
+}
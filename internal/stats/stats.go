@@ -0,0 +1,142 @@
+// Package stats summarizes the session history
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"gymtimer/internal/history"
+	"gymtimer/internal/score"
+)
+
+// How far back the charts reach
+const (
+	Weeks = 12
+	Days  = 30
+)
+
+// Summary is the training history at a glance
+type Summary struct {
+	Sessions int
+	Active   time.Duration // Total time the timer ran
+
+	Weekly []time.Duration // Training time per week, oldest first, ending this week
+	Daily  []time.Duration // Training time per day, oldest first, ending today
+
+	PerMode map[string]int // Sessions per mode
+
+	CurrentStreak int // Consecutive days with a session, up to today or yesterday
+	LongestStreak int
+
+	Records []Record // Best score per workout, by title
+}
+
+// Record is the best result for one workout
+type Record struct {
+	Workout string
+	Score   score.Score
+	Date    time.Time
+	Count   int // Scored sessions of this workout
+}
+
+// Compute summarizes sessions as of now
+func Compute(sessions []history.Session, now time.Time) Summary {
+	s := Summary{
+		Sessions: len(sessions),
+		Weekly:   make([]time.Duration, Weeks),
+		Daily:    make([]time.Duration, Days),
+		PerMode:  make(map[string]int),
+	}
+
+	today := day(now)
+	thisWeek := weekStart(now)
+	trained := make(map[time.Time]bool)
+	best := make(map[string]*Record)
+
+	for _, sess := range sessions {
+		active := sess.Active()
+		s.Active += active
+		s.PerMode[sess.Mode]++
+
+		start := sess.Start.In(now.Location())
+		d := day(start)
+		trained[d] = true
+
+		if i := Days - 1 - daysBetween(d, today); i >= 0 && i < Days {
+			s.Daily[i] += active
+		}
+		if i := Weeks - 1 - daysBetween(weekStart(start), thisWeek)/7; i >= 0 && i < Weeks {
+			s.Weekly[i] += active
+		}
+
+		if sess.Score != nil {
+			title := sess.Title()
+			r, ok := best[title]
+			if !ok {
+				r = &Record{Workout: title, Score: *sess.Score, Date: sess.Start}
+				best[title] = r
+			} else if sess.Score.Kind == r.Score.Kind && sess.Score.Compare(r.Score) > 0 {
+				r.Score, r.Date = *sess.Score, sess.Start
+			}
+			r.Count++
+		}
+	}
+
+	s.CurrentStreak, s.LongestStreak = streaks(trained, today)
+
+	for _, r := range best {
+		s.Records = append(s.Records, *r)
+	}
+	sort.Slice(s.Records, func(i, j int) bool { return s.Records[i].Workout < s.Records[j].Workout })
+	return s
+}
+
+// streaks returns the run of training days ending today (or yesterday, so
+// a streak is not lost before today's workout) and the longest run ever
+func streaks(trained map[time.Time]bool, today time.Time) (current, longest int) {
+	days := make([]time.Time, 0, len(trained))
+	for d := range trained {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	run := 0
+	for i, d := range days {
+		if i > 0 && daysBetween(days[i-1], d) == 1 {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	start := today
+	if !trained[start] {
+		start = start.AddDate(0, 0, -1)
+	}
+	for d := start; trained[d]; d = d.AddDate(0, 0, -1) {
+		current++
+	}
+	return current, longest
+}
+
+// day returns midnight at the start of t's day
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns midnight on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	d := day(t)
+	return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+}
+
+// daysBetween counts calendar days from a to b, ignoring daylight saving
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	ua := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	ub := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
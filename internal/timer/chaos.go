@@ -0,0 +1,117 @@
+package timer
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Largest seed handed out by NewSeed, short enough to read out in class
+const maxSeed = 9999
+
+// NewSeed picks a seed for a new chaos session
+func NewSeed() int64 {
+	return rand.Int63n(maxSeed) + 1
+}
+
+// chaosRound is the work and rest drawn for one round of a chaos session
+type chaosRound struct {
+	work, rest time.Duration
+}
+
+// drawChaos draws every round of a chaos session from the seed. Work and
+// rest fall between their shortest and longest in whole seconds, and the
+// rounds fill Duration exactly: when too little time is left for another
+// round the last rest takes it all.
+func (t *Timer) drawChaos() []chaosRound {
+	rng := rand.New(rand.NewSource(t.Seed))
+	draw := func(lo, hi time.Duration) time.Duration {
+		secs := int64(max(hi, lo)-lo) / int64(time.Second)
+		return lo + time.Duration(rng.Int63n(secs+1))*time.Second
+	}
+
+	var rounds []chaosRound
+	left := t.Duration
+	for left > 0 {
+		work := min(draw(max(time.Second, t.WorkDuration), t.WorkMax), left)
+		left -= work
+		rest := min(draw(t.RestDuration, t.RestMax), left)
+		if left-rest < max(time.Second, t.WorkDuration) {
+			rest = left
+		}
+		left -= rest
+		rounds = append(rounds, chaosRound{work, rest})
+	}
+	return rounds
+}
+
+// Draw lays out the rounds of a chaos session. Call it after changing the
+// ranges, duration or seed.
+func (t *Timer) Draw() {
+	t.chaos = nil
+	if t.Mode == ModeChaos {
+		t.chaos = t.drawChaos()
+		t.TotalRounds = len(t.chaos)
+	}
+}
+
+// chaosRounds returns the drawn rounds, drawing them again for a timer
+// restored from a snapshot
+func (t *Timer) chaosRounds() []chaosRound {
+	if t.chaos == nil {
+		t.chaos = t.drawChaos()
+	}
+	return t.chaos
+}
+
+// chaosRound returns the drawn intervals of the given round
+func (t *Timer) chaosRound(round int) chaosRound {
+	rounds := t.chaosRounds()
+	if round < 1 || round > len(rounds) {
+		return chaosRound{}
+	}
+	return rounds[round-1]
+}
+
+// NextInterval returns the phase and length of the interval after the
+// current one, or false during the last
+func (t *Timer) NextInterval() (Phase, time.Duration, bool) {
+	if t.Phase == PhaseWork {
+		if rest := t.RestFor(t.Round); rest > 0 {
+			return PhaseRest, rest, true
+		}
+	}
+	if t.Round >= t.TotalRounds {
+		return PhaseWork, 0, false
+	}
+	return PhaseWork, t.WorkFor(t.Round + 1), true
+}
+
+// SessionRemaining returns how long is left of a chaos session
+func (t *Timer) SessionRemaining() time.Duration {
+	done := t.Elapsed
+	for i, r := range t.chaosRounds() {
+		switch {
+		case i+1 < t.Round:
+			done += r.work + r.rest
+		case i+1 == t.Round && t.Phase == PhaseRest:
+			done += r.work
+		}
+	}
+	return max(0, t.Duration-done)
+}
+
+// ChaosSummary describes the ranges and seed, e.g. "15s-45s/10s-30s seed
+// 4821"
+func (t *Timer) ChaosSummary() string {
+	return fmt.Sprintf("%s/%s seed %d", span(t.WorkDuration, t.WorkMax), span(t.RestDuration, t.RestMax), t.Seed)
+}
+
+// span writes a range of durations, e.g. "15s-45s", or one duration when
+// the range is empty
+func span(lo, hi time.Duration) string {
+	if hi <= lo {
+		return lo.String()
+	}
+	return lo.String() + "-" + hi.String()
+}
@@ -0,0 +1,46 @@
+package timer
+
+import "fmt"
+
+// DefaultStations names n stations "Station 1", "Station 2", ...
+func DefaultStations(n int) []string {
+	stations := make([]string, n)
+	for i := range stations {
+		stations[i] = fmt.Sprintf("Station %d", i+1)
+	}
+	return stations
+}
+
+// SetLaps sets the number of rounds for a circuit of laps laps. Each round
+// is one stint of work at a station followed by the move to the next;
+// every group visits each station once per lap.
+func (t *Timer) SetLaps(laps int) {
+	t.TotalRounds = laps * max(1, len(t.Stations))
+}
+
+// Laps returns how many times each group goes round the stations
+func (t *Timer) Laps() int {
+	if len(t.Stations) == 0 {
+		return 0
+	}
+	return t.TotalRounds / len(t.Stations)
+}
+
+// Lap returns the current lap, counting from 1
+func (t *Timer) Lap() int {
+	if len(t.Stations) == 0 {
+		return 0
+	}
+	return (min(t.Round, t.TotalRounds)-1)/len(t.Stations) + 1
+}
+
+// Groups returns how many groups rotate: one starts at each station
+func (t *Timer) Groups() int {
+	return len(t.Stations)
+}
+
+// StationOf returns the station group g (counting from 1) works at in the
+// given round
+func (t *Timer) StationOf(group, round int) string {
+	return t.Stations[(group-1+round-1)%len(t.Stations)]
+}
@@ -25,13 +25,7 @@ func (c *CustomTimer) Tick() {
 	}
 
 	c.Elapsed += time.Second
-
-	var currentDuration time.Duration
-	if c.Phase == PhaseWork {
-		currentDuration = c.WorkDuration
-	} else {
-		currentDuration = c.RestDuration
-	}
+	currentDuration := c.IntervalDuration()
 
 	// 3-2-1 countdown beeps
 	remaining := currentDuration - c.Elapsed
@@ -64,20 +58,19 @@ func (c *CustomTimer) Tick() {
 
 // CurrentIntervalRemaining returns time remaining in current work/rest interval
 func (c *CustomTimer) CurrentIntervalRemaining() time.Duration {
-	var currentDuration time.Duration
-	if c.Phase == PhaseWork {
-		currentDuration = c.WorkDuration
-	} else {
-		currentDuration = c.RestDuration
-	}
-	remaining := currentDuration - c.Elapsed
+	remaining := c.IntervalDuration() - c.Elapsed
 	if remaining < 0 {
 		return 0
 	}
 	return remaining
 }
 
-// TotalWorkoutDuration calculates total workout time
+// TotalWorkoutDuration calculates total workout time, following the
+// progression when intervals change from round to round
 func (c *CustomTimer) TotalWorkoutDuration() time.Duration {
-	return time.Duration(c.TotalRounds) * (c.WorkDuration + c.RestDuration)
+	var total time.Duration
+	for round := 1; round <= c.TotalRounds; round++ {
+		total += c.WorkFor(round) + c.RestFor(round)
+	}
+	return total
 }
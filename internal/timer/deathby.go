@@ -0,0 +1,12 @@
+package timer
+
+// RepTarget returns how many reps a Death by minute asks for
+func (t *Timer) RepTarget(round int) int {
+	return t.StartReps + (round-1)*t.RepStep
+}
+
+// CompletedMinutes returns how many Death by minutes the athlete made: those
+// before the one they failed, or every minute if they never did
+func (t *Timer) CompletedMinutes() int {
+	return max(0, min(t.Round-1, t.TotalRounds))
+}
@@ -2,7 +2,8 @@ package timer
 
 import "time"
 
-// EMOMTimer handles EMOM (Every Minute On the Minute) logic
+// EMOMTimer handles EMOM (Every Minute On the Minute) logic, with rounds
+// of WorkDuration
 type EMOMTimer struct {
 	*Timer
 }
@@ -11,6 +12,7 @@ type EMOMTimer struct {
 func NewEMOM(rounds int) *EMOMTimer {
 	t := New()
 	t.Mode = ModeEMOM
+	t.WorkDuration = time.Minute
 	t.TotalRounds = rounds
 	return &EMOMTimer{Timer: t}
 }
@@ -23,8 +25,8 @@ func (e *EMOMTimer) Tick() {
 
 	e.Elapsed += time.Second
 
-	// Check if minute is complete
-	if e.Elapsed >= time.Minute {
+	// Check if the round is complete
+	if e.Elapsed >= e.WorkDuration {
 		e.Elapsed = 0
 		e.Round++
 
@@ -38,7 +40,7 @@ func (e *EMOMTimer) Tick() {
 	}
 
 	// 3-2-1 countdown beeps
-	remaining := time.Minute - e.Elapsed
+	remaining := e.WorkDuration - e.Elapsed
 	if remaining <= 3*time.Second && remaining > 0 {
 		secs := int(remaining.Seconds())
 		if e.OnCountdownTick != nil {
@@ -47,12 +49,12 @@ func (e *EMOMTimer) Tick() {
 	}
 }
 
-// SecondsInMinute returns elapsed seconds in current minute
+// SecondsInMinute returns elapsed seconds in the current round
 func (e *EMOMTimer) SecondsInMinute() int {
-	return int(e.Elapsed.Seconds()) % 60
+	return int(e.Elapsed.Seconds())
 }
 
-// SecondsRemaining returns seconds remaining in current minute
+// SecondsRemaining returns seconds remaining in the current round
 func (e *EMOMTimer) SecondsRemaining() int {
-	return 60 - e.SecondsInMinute()
+	return int((e.WorkDuration - e.Elapsed).Seconds())
 }
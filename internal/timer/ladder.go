@@ -0,0 +1,105 @@
+package timer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Progression is how custom intervals change from round to round
+type Progression string
+
+const (
+	// Every round takes the same time
+	Fixed Progression = ""
+	// Each round adds the step, e.g. 30/40/50/60s, or with a negative step
+	// counts down
+	Ladder Progression = "ladder"
+	// Rounds climb by the step to the middle and come back down, e.g.
+	// 30/40/50/40/30s
+	Pyramid Progression = "pyramid"
+)
+
+// Shortest work interval a progression can step down to
+const minStepWork = time.Second
+
+// ParseProgression looks up a progression by name; "fixed" or "" for none
+func ParseProgression(name string) (Progression, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "fixed":
+		return Fixed, true
+	case "ladder":
+		return Ladder, true
+	case "pyramid":
+		return Pyramid, true
+	default:
+		return Fixed, false
+	}
+}
+
+// Progresses reports whether intervals change from round to round. Only
+// custom workouts progress.
+func (t *Timer) Progresses() bool {
+	return t.Mode == ModeCustom && t.Progression != Fixed && (t.WorkStep != 0 || t.RestStep != 0)
+}
+
+// steps returns how many steps the given round is from the first
+func (t *Timer) steps(round int) int {
+	switch t.Progression {
+	case Ladder:
+		return round - 1
+	case Pyramid:
+		return min(round-1, t.TotalRounds-round)
+	default:
+		return 0
+	}
+}
+
+// WorkFor returns the length of the work interval of the given round, as
+// drawn in a chaos session
+func (t *Timer) WorkFor(round int) time.Duration {
+	if t.Mode == ModeChaos {
+		return t.chaosRound(round).work
+	}
+	if !t.Progresses() {
+		return t.WorkDuration
+	}
+	return max(minStepWork, t.WorkDuration+time.Duration(t.steps(round))*t.WorkStep)
+}
+
+// RestFor returns the length of the rest interval of the given round,
+// which is the rest between sets at the end of a set, or as drawn in a
+// chaos session
+func (t *Timer) RestFor(round int) time.Duration {
+	if t.Mode == ModeChaos {
+		return t.chaosRound(round).rest
+	}
+	if t.EndsSet(round) {
+		return t.SetRest
+	}
+	if !t.Progresses() {
+		return t.RestDuration
+	}
+	return max(0, t.RestDuration+time.Duration(t.steps(round))*t.RestStep)
+}
+
+// ProgressionSummary describes the steps, e.g. "ladder +10s" or
+// "pyramid +10s/+5s", or "" for fixed intervals
+func (t *Timer) ProgressionSummary() string {
+	if !t.Progresses() {
+		return ""
+	}
+	s := fmt.Sprintf("%s %s", t.Progression, signed(t.WorkStep))
+	if t.RestStep != 0 {
+		s += "/" + signed(t.RestStep)
+	}
+	return s
+}
+
+// signed writes a step with its sign, e.g. "+10s"
+func signed(d time.Duration) string {
+	if d < 0 {
+		return d.String()
+	}
+	return "+" + d.String()
+}
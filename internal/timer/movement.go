@@ -0,0 +1,99 @@
+package timer
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Movement is what the athletes do in one round, e.g. "12 KB swings" with
+// the note "24/16 kg"
+type Movement struct {
+	Label string `json:"label"`
+	Note  string `json:"note,omitempty"`
+}
+
+// UnmarshalJSON reads a movement, also accepting a bare label string
+func (mv *Movement) UnmarshalJSON(b []byte) error {
+	var label string
+	if err := json.Unmarshal(b, &label); err == nil {
+		*mv = Movement{Label: label}
+		return nil
+	}
+
+	type plain Movement
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*mv = Movement(p)
+	return nil
+}
+
+// IsZero reports whether the movement has neither label nor note
+func (mv Movement) IsZero() bool {
+	return strings.TrimSpace(mv.Label) == "" && strings.TrimSpace(mv.Note) == ""
+}
+
+// HasMovements reports whether rounds are labelled with movements. Only
+// EMOM, Tabata and custom workouts carry them.
+func (t *Timer) HasMovements() bool {
+	switch t.Mode {
+	case ModeEMOM, ModeTabata, ModeCustom:
+		return len(t.Movements) > 0
+	default:
+		return false
+	}
+}
+
+// Rotates reports whether EMOM rounds cycle through the movements, e.g.
+// odd minutes swings and even minutes burpees
+func (t *Timer) Rotates() bool {
+	return t.Mode == ModeEMOM && t.Rotation > 1
+}
+
+// Station returns which movement of the rotation the given round is,
+// counting from 1
+func (t *Timer) Station(round int) int {
+	return (round-1)%t.Rotation + 1
+}
+
+// Movement returns the movement of the given round, if it has one
+func (t *Timer) Movement(round int) (Movement, bool) {
+	if round < 1 || round > t.TotalRounds {
+		return Movement{}, false
+	}
+	i := round - 1
+	if t.Rotates() {
+		i = t.Station(round) - 1
+	}
+	if i >= len(t.Movements) {
+		return Movement{}, false
+	}
+	mv := t.Movements[i]
+	return mv, !mv.IsZero()
+}
+
+// CurrentMovement returns the movement of the current round
+func (t *Timer) CurrentMovement() (Movement, bool) {
+	return t.Movement(min(t.Round, t.TotalRounds))
+}
+
+// NextMovement returns the movement of the next round
+func (t *Timer) NextMovement() (Movement, bool) {
+	return t.Movement(t.Round + 1)
+}
+
+// SetMovement labels a round, or a station of the rotation, growing the
+// list as needed and dropping trailing ones without a movement
+func (t *Timer) SetMovement(round int, mv Movement) {
+	if round < 1 {
+		return
+	}
+	for len(t.Movements) < round {
+		t.Movements = append(t.Movements, Movement{})
+	}
+	t.Movements[round-1] = mv
+	for len(t.Movements) > 0 && t.Movements[len(t.Movements)-1].IsZero() {
+		t.Movements = t.Movements[:len(t.Movements)-1]
+	}
+}
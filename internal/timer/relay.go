@@ -0,0 +1,53 @@
+package timer
+
+import "time"
+
+// Relay reports whether athletes take turns, one round each. Only EMOM and
+// custom workouts rotate.
+func (t *Timer) Relay() bool {
+	return len(t.Athletes) > 1 && (t.Mode == ModeEMOM || t.Mode == ModeCustom)
+}
+
+// Athlete returns who works in the given round
+func (t *Timer) Athlete(round int) string {
+	return t.Athletes[(round-1)%len(t.Athletes)]
+}
+
+// CurrentAthlete returns who works in the current round
+func (t *Timer) CurrentAthlete() string {
+	return t.Athlete(max(1, min(t.Round, t.TotalRounds)))
+}
+
+// NextAthlete returns who works in the next round, or "" during the last
+func (t *Timer) NextAthlete() string {
+	if t.Round >= t.TotalRounds {
+		return ""
+	}
+	return t.Athlete(t.Round + 1)
+}
+
+// WorkTotals returns how long each athlete has worked so far, in the order
+// of Athletes
+func (t *Timer) WorkTotals() []time.Duration {
+	totals := make([]time.Duration, len(t.Athletes))
+	if len(t.Athletes) == 0 {
+		return totals
+	}
+
+	done := min(t.Round-1, t.TotalRounds)
+	for round := 1; round <= done; round++ {
+		totals[(round-1)%len(t.Athletes)] += t.WorkFor(round)
+	}
+	if t.Round > t.TotalRounds {
+		return totals
+	}
+
+	// The round in progress
+	i := (t.Round - 1) % len(t.Athletes)
+	if t.Phase == PhaseWork {
+		totals[i] += t.Elapsed
+	} else {
+		totals[i] += t.WorkFor(t.Round)
+	}
+	return totals
+}
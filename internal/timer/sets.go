@@ -0,0 +1,41 @@
+package timer
+
+// HasSets reports whether the rounds are split into sets with a longer
+// rest between them. Only Tabata and custom workouts have sets.
+func (t *Timer) HasSets() bool {
+	return (t.Mode == ModeTabata || t.Mode == ModeCustom) && t.Sets > 1
+}
+
+// RoundsPerSet returns how many rounds make up each set
+func (t *Timer) RoundsPerSet() int {
+	return t.TotalRounds / max(1, t.Sets)
+}
+
+// SetSets splits the workout into n sets, keeping the rounds in each
+func (t *Timer) SetSets(n int) {
+	perSet := t.RoundsPerSet()
+	t.Sets = max(1, n)
+	t.TotalRounds = perSet * t.Sets
+}
+
+// SetOf returns the set the given round belongs to, counting from 1
+func (t *Timer) SetOf(round int) int {
+	if !t.HasSets() {
+		return 1
+	}
+	return (round-1)/t.RoundsPerSet() + 1
+}
+
+// RoundInSet returns the given round's place in its set, counting from 1
+func (t *Timer) RoundInSet(round int) int {
+	if !t.HasSets() {
+		return round
+	}
+	return (round-1)%t.RoundsPerSet() + 1
+}
+
+// EndsSet reports whether the rest after the given round is the rest
+// between two sets
+func (t *Timer) EndsSet(round int) bool {
+	return t.HasSets() && round < t.TotalRounds && round%t.RoundsPerSet() == 0
+}
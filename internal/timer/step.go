@@ -0,0 +1,63 @@
+package timer
+
+// Step reports what happened during a call to Advance
+type Step struct {
+	PhaseChanged bool // Work/rest phase flipped
+	RoundChanged bool // A new round began
+	SetEnded     bool // A set ended and the rest between sets began
+	Finished     bool // The workout just completed
+}
+
+// CountdownBeep returns 3, 2 or 1 when the coming tick is one of the last
+// three seconds of the interval, otherwise 0. Call it before Advance.
+func (t *Timer) CountdownBeep() int {
+	if !t.Running {
+		return 0
+	}
+	secs := int(t.TimeRemaining().Seconds())
+	if secs <= 3 && secs > 0 {
+		return secs
+	}
+	return 0
+}
+
+// Advance moves a running countdown timer forward one second and applies
+// the interval transitions for the current mode
+func (t *Timer) Advance() Step {
+	var step Step
+	if !t.Running {
+		return step
+	}
+
+	t.Tick()
+
+	switch t.Mode {
+	case ModeEMOM, ModeDeathBy:
+		if t.Elapsed >= t.WorkDuration {
+			t.Elapsed = 0
+			t.Round++
+			step.RoundChanged = true
+		}
+	case ModeTabata, ModeCustom, ModeCircuit, ModeChaos:
+		if t.Elapsed >= t.IntervalDuration() {
+			t.Elapsed = 0
+			// A round without rest goes straight on to the next
+			if t.Phase == PhaseWork && t.RestFor(t.Round) > 0 {
+				t.Phase = PhaseRest
+				step.SetEnded = t.EndsSet(t.Round)
+				step.PhaseChanged = true
+			} else {
+				step.PhaseChanged = t.Phase != PhaseWork
+				t.Phase = PhaseWork
+				t.Round++
+				step.RoundChanged = true
+			}
+		}
+	}
+
+	if t.IsFinished() {
+		t.Running = false
+		step.Finished = true
+	}
+	return step
+}
@@ -25,13 +25,7 @@ func (tb *TabataTimer) Tick() {
 	}
 
 	tb.Elapsed += time.Second
-
-	var currentDuration time.Duration
-	if tb.Phase == PhaseWork {
-		currentDuration = tb.WorkDuration
-	} else {
-		currentDuration = tb.RestDuration
-	}
+	currentDuration := tb.IntervalDuration()
 
 	// 3-2-1 countdown beeps
 	remaining := currentDuration - tb.Elapsed
@@ -64,13 +58,7 @@ func (tb *TabataTimer) Tick() {
 
 // CurrentIntervalRemaining returns time remaining in current work/rest interval
 func (tb *TabataTimer) CurrentIntervalRemaining() time.Duration {
-	var currentDuration time.Duration
-	if tb.Phase == PhaseWork {
-		currentDuration = tb.WorkDuration
-	} else {
-		currentDuration = tb.RestDuration
-	}
-	remaining := currentDuration - tb.Elapsed
+	remaining := tb.IntervalDuration() - tb.Elapsed
 	if remaining < 0 {
 		return 0
 	}
@@ -1,6 +1,10 @@
 package timer
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Mode represents the timer mode
 type Mode int
@@ -12,6 +16,10 @@ const (
 	ModeAMRAP
 	ModeCustom
 	ModeStopwatch
+	ModeForTime
+	ModeCircuit
+	ModeDeathBy
+	ModeChaos
 )
 
 // Phase represents work or rest phase
@@ -25,7 +33,8 @@ const (
 
 // Timer holds the core timer state
 type Timer struct {
-	Duration    time.Duration // Total duration for countdown modes
+	Name        string        // Workout name, if loaded from a workout file
+	Duration    time.Duration // Total duration for countdown modes, time cap for For Time
 	Elapsed     time.Duration // Time elapsed in current interval
 	Running     bool
 	Mode        Mode
@@ -37,13 +46,52 @@ type Timer struct {
 	WorkDuration time.Duration
 	RestDuration time.Duration
 
+	// Tabata and custom: the rounds split into this many sets, with SetRest
+	// between them instead of the last round's rest
+	Sets    int           `json:",omitempty"`
+	SetRest time.Duration `json:",omitempty"`
+
+	// Custom: how work and rest change each round, e.g. a ladder adding
+	// WorkStep every round
+	Progression Progression   `json:",omitempty"`
+	WorkStep    time.Duration `json:",omitempty"`
+	RestStep    time.Duration `json:",omitempty"`
+
 	// Countdown before start
 	CountdownRemaining int
 
+	// For Time: the athlete finished before the cap. Death by: the athlete
+	// failed to make the minute's reps.
+	Done bool
+
+	// Chaos: work and rest are drawn at random between WorkDuration and
+	// WorkMax and between RestDuration and RestMax until Duration is filled.
+	// The same seed draws the same session.
+	WorkMax time.Duration `json:",omitempty"`
+	RestMax time.Duration `json:",omitempty"`
+	Seed    int64         `json:",omitempty"`
+	chaos   []chaosRound  // The rounds drawn from the seed, see Draw
+
+	// Death by: reps in the first minute, and how many each minute adds
+	StartReps int `json:",omitempty"`
+	RepStep   int `json:",omitempty"`
+
+	// Partners or team members taking turns, one round each
+	Athletes []string `json:",omitempty"`
+
+	// Circuit: the stations groups rotate through, one per round
+	Stations []string `json:",omitempty"`
+
+	// What the athletes do in each round, in order (EMOM, Tabata, custom)
+	Movements []Movement `json:",omitempty"`
+
+	// EMOM: rounds cycle through this many movements, 0 for no rotation
+	Rotation int `json:",omitempty"`
+
 	// Callbacks
-	OnIntervalChange func(phase Phase)
-	OnCountdownTick  func(remaining int)
-	OnRoundChange    func(round int)
+	OnIntervalChange func(phase Phase)   `json:"-"`
+	OnCountdownTick  func(remaining int) `json:"-"`
+	OnRoundChange    func(round int)     `json:"-"`
 }
 
 // New creates a new timer with default settings
@@ -80,6 +128,17 @@ func (t *Timer) Reset() {
 	t.Phase = PhaseWork
 	t.Running = false
 	t.CountdownRemaining = 0
+	t.Done = false
+}
+
+// Finish ends a For Time workout at the current elapsed time, or a Death
+// by workout at the minute the athlete failed
+func (t *Timer) Finish() {
+	if (t.Mode != ModeForTime && t.Mode != ModeDeathBy) || t.IsFinished() {
+		return
+	}
+	t.Done = true
+	t.Running = false
 }
 
 // Tick advances the timer by one second
@@ -99,30 +158,49 @@ func (t *Timer) TimeRemaining() time.Duration {
 			return 0
 		}
 		return remaining
-	case ModeTabata, ModeCustom:
-		var intervalDuration time.Duration
-		if t.Phase == PhaseWork {
-			intervalDuration = t.WorkDuration
-		} else {
-			intervalDuration = t.RestDuration
-		}
-		remaining := intervalDuration - t.Elapsed
+	case ModeTabata, ModeCustom, ModeCircuit, ModeChaos:
+		remaining := t.IntervalDuration() - t.Elapsed
 		if remaining < 0 {
 			return 0
 		}
 		return remaining
-	case ModeEMOM:
-		// EMOM counts up within each minute
-		remaining := time.Minute - t.Elapsed
+	case ModeEMOM, ModeDeathBy:
+		// EMOM counts down within each interval
+		remaining := t.WorkDuration - t.Elapsed
 		if remaining < 0 {
 			return 0
 		}
 		return remaining
+	case ModeForTime:
+		// Time left before the cap, if there is one
+		if t.Duration <= 0 || t.Elapsed >= t.Duration {
+			return 0
+		}
+		return t.Duration - t.Elapsed
 	default:
 		return 0
 	}
 }
 
+// IntervalDuration returns the length of the current work or rest interval.
+// For EMOM this is the length of each round.
+func (t *Timer) IntervalDuration() time.Duration {
+	if t.Phase == PhaseWork {
+		return t.WorkFor(t.Round)
+	}
+	return t.RestFor(t.Round)
+}
+
+// HasRounds reports whether the current mode counts rounds
+func (t *Timer) HasRounds() bool {
+	switch t.Mode {
+	case ModeEMOM, ModeTabata, ModeCustom, ModeCircuit, ModeDeathBy, ModeChaos:
+		return true
+	default:
+		return false
+	}
+}
+
 // ElapsedInInterval returns elapsed time in current interval
 func (t *Timer) ElapsedInInterval() time.Duration {
 	return t.Elapsed
@@ -133,10 +211,14 @@ func (t *Timer) IsFinished() bool {
 	switch t.Mode {
 	case ModeAMRAP:
 		return t.Elapsed >= t.Duration
-	case ModeTabata, ModeCustom:
+	case ModeTabata, ModeCustom, ModeCircuit, ModeChaos:
 		return t.Round > t.TotalRounds
 	case ModeEMOM:
 		return t.Round > t.TotalRounds
+	case ModeDeathBy:
+		return t.Done || t.Round > t.TotalRounds
+	case ModeForTime:
+		return t.Done || (t.Duration > 0 && t.Elapsed >= t.Duration)
 	default:
 		return false
 	}
@@ -153,14 +235,37 @@ func (t *Timer) SetMode(mode Mode) {
 		t.WorkDuration = 20 * time.Second
 		t.RestDuration = 10 * time.Second
 		t.TotalRounds = 8
+		t.Sets, t.SetRest = 1, time.Minute
 	case ModeEMOM:
+		t.WorkDuration = time.Minute // Interval length ("every")
 		t.TotalRounds = 10
 	case ModeAMRAP:
 		t.Duration = 20 * time.Minute
+	case ModeForTime:
+		t.Duration = 0 // No time cap
 	case ModeCustom:
 		t.WorkDuration = 30 * time.Second
 		t.RestDuration = 15 * time.Second
 		t.TotalRounds = 5
+		t.Sets, t.SetRest = 1, time.Minute
+	case ModeCircuit:
+		t.WorkDuration = 45 * time.Second
+		t.RestDuration = 15 * time.Second // Time to move to the next station
+		if len(t.Stations) == 0 {
+			t.Stations = DefaultStations(4)
+		}
+		t.SetLaps(1)
+	case ModeDeathBy:
+		t.WorkDuration = time.Minute
+		t.TotalRounds = 30 // The most minutes anyone is expected to last
+		t.StartReps = 1
+		t.RepStep = 1
+	case ModeChaos:
+		t.Duration = 20 * time.Minute
+		t.WorkDuration, t.WorkMax = 15*time.Second, 45*time.Second
+		t.RestDuration, t.RestMax = 10*time.Second, 30*time.Second
+		t.Seed = NewSeed()
+		t.Draw()
 	}
 }
 
@@ -179,11 +284,98 @@ func (t *Timer) ModeName() string {
 		return "CUSTOM"
 	case ModeStopwatch:
 		return "STOPWATCH"
+	case ModeForTime:
+		return "FOR TIME"
+	case ModeCircuit:
+		return "CIRCUIT"
+	case ModeDeathBy:
+		return "DEATH BY"
+	case ModeChaos:
+		return "CHAOS"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// Summary describes the workout settings in a few words, e.g.
+// "TABATA 8 x 20s/10s", "EMOM 12" or "CHAOS 20 15s-45s/10s-30s seed 4821"
+func (t *Timer) Summary() string {
+	switch t.Mode {
+	case ModeEMOM:
+		if t.WorkDuration == time.Minute {
+			return fmt.Sprintf("EMOM %d", t.TotalRounds)
+		}
+		return fmt.Sprintf("EMOM %d x %s", t.TotalRounds, t.WorkDuration)
+	case ModeTabata, ModeCustom:
+		s := fmt.Sprintf("%s %d x %s/%s", t.ModeName(), t.RoundsPerSet(), t.WorkDuration, t.RestDuration)
+		if t.Progresses() {
+			s += " " + t.ProgressionSummary()
+		}
+		if t.HasSets() {
+			s += fmt.Sprintf(" %d sets %s", t.Sets, t.SetRest)
+		}
+		return s
+	case ModeAMRAP:
+		if t.Duration%time.Minute != 0 {
+			return fmt.Sprintf("AMRAP %s", t.Duration)
+		}
+		return fmt.Sprintf("AMRAP %d", int(t.Duration.Minutes()))
+	case ModeForTime:
+		switch {
+		case t.Duration <= 0:
+			return "FOR TIME"
+		case t.Duration%time.Minute != 0:
+			return fmt.Sprintf("FOR TIME %s", t.Duration)
+		default:
+			return fmt.Sprintf("FOR TIME %d", int(t.Duration.Minutes()))
+		}
+	case ModeCircuit:
+		return fmt.Sprintf("CIRCUIT %d x %d stations %s/%s", t.Laps(), len(t.Stations), t.WorkDuration, t.RestDuration)
+	case ModeDeathBy:
+		if t.StartReps == 1 && t.RepStep == 1 {
+			return "DEATH BY"
+		}
+		return fmt.Sprintf("DEATH BY %d+%d", t.StartReps, t.RepStep)
+	case ModeChaos:
+		if t.Duration%time.Minute != 0 {
+			return fmt.Sprintf("CHAOS %s %s", t.Duration, t.ChaosSummary())
+		}
+		return fmt.Sprintf("CHAOS %d %s", int(t.Duration.Minutes()), t.ChaosSummary())
+	default:
+		return t.ModeName()
+	}
+}
+
+// ParseMode looks up a mode by its name (case-insensitive), e.g. "tabata"
+// or "for time"
+func ParseMode(name string) (Mode, bool) {
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	switch strings.ToUpper(strings.Join(strings.Fields(name), " ")) {
+	case "CLOCK":
+		return ModeClock, true
+	case "EMOM":
+		return ModeEMOM, true
+	case "TABATA":
+		return ModeTabata, true
+	case "AMRAP":
+		return ModeAMRAP, true
+	case "CUSTOM":
+		return ModeCustom, true
+	case "STOPWATCH":
+		return ModeStopwatch, true
+	case "FOR TIME", "FORTIME":
+		return ModeForTime, true
+	case "CIRCUIT":
+		return ModeCircuit, true
+	case "DEATH BY", "DEATHBY":
+		return ModeDeathBy, true
+	case "CHAOS":
+		return ModeChaos, true
+	default:
+		return ModeClock, false
+	}
+}
+
 // PhaseName returns the string name of the current phase
 func (t *Timer) PhaseName() string {
 	switch t.Phase {
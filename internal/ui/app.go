@@ -2,9 +2,21 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gymtimer/internal/audio"
+	"gymtimer/internal/benchmark"
+	"gymtimer/internal/cue"
+	"gymtimer/internal/events"
+	"gymtimer/internal/export"
+	"gymtimer/internal/heat"
+	"gymtimer/internal/history"
+	"gymtimer/internal/leaderboard"
+	"gymtimer/internal/schedule"
+	"gymtimer/internal/score"
+	"gymtimer/internal/session"
+	"gymtimer/internal/stats"
 	"gymtimer/internal/timer"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,8 +31,15 @@ const (
 	StateSetup
 	StatePaused
 	StateFinished
+	StateResume // Asking whether to resume an interrupted session
 )
 
+// Save the session snapshot every this many ticks
+const saveInterval = 5
+
+// How long a finished class stays on screen before returning to the clock
+const classFinishedHold = time.Minute
+
 // SettingField represents which setting is being edited
 type SettingField int
 
@@ -29,28 +48,114 @@ const (
 	SettingRest
 	SettingRounds
 	SettingDuration
+	SettingLanes
+	SettingStations
+	SettingProgression
+	SettingStep
+	SettingReps
+	SettingSets
+	SettingSetRest
+	SettingWorkMax
+	SettingRestMax
+	SettingSeed
 )
 
 // Model is the main Bubbletea model
 type Model struct {
-	timer       *timer.Timer
-	stopwatch   *timer.Stopwatch
-	audio       *audio.Player
-	keys        KeyMap
-	width       int
-	height      int
-	state       AppState
+	timer        *timer.Timer
+	stopwatch    *timer.Stopwatch
+	audio        *audio.Player
+	keys         KeyMap
+	width        int
+	height       int
+	state        AppState
 	settingField SettingField
 
 	// Track last countdown beep to avoid duplicates
 	lastCountdownBeep int
+
+	// Event listeners (hooks etc.) and whether the current workout has begun
+	events  *events.Dispatcher
+	started bool
+
+	// Start the loaded workout as soon as the program runs
+	autoStart bool
+
+	// Crash recovery: where snapshots go, and a snapshot offered for resume
+	sessionPath string
+	resume      *session.Snapshot
+	ticks       int
+
+	// A workout armed to start at a wall-clock time
+	armed   *timer.Timer
+	startAt time.Time
+
+	// Day plan: classes are armed in turn while the clock is showing
+	plan       *schedule.Schedule
+	planAfter  time.Time // Skip classes starting before this
+	armedClass string    // Label of the armed class, if armed from the plan
+	classTimer *timer.Timer
+	finishedAt time.Time
+
+	// Exporting the just-finished session
+	historyPath  string
+	exportDir    string
+	exportFormat string
+	notice       string // Result of the last export, shown until the next key
+
+	// Logging a score on the finished screen
+	scoring    bool
+	scoreInput string
+
+	stats *stats.Summary // Shown instead of the timer while set
+
+	// Benchmark workouts
+	benchmark *benchmark.Benchmark // The loaded benchmark, if any
+	library   []benchmark.Benchmark
+	picking   bool // Choosing from the library
+	pick      int
+	best      *score.Score  // Best result before this session
+	result    *finishResult // How the finished workout went
+
+	// Heat: several athletes racing one clock, one per lane
+	heat        *heat.Heat
+	heatLanes   int  // Lanes chosen in setup, 0 for no heat
+	heatResults bool // Showing the ranked results
+	scoreLane   int  // Lane whose score is being entered, 0 for the session
+
+	// Competition leaderboard, shown instead of the timer while set
+	board     *leaderboard.Competition
+	boardPath string
+	boardPage int // 0 for the overall standings, then one per workout
+
+	// Typing movement labels and notes in setup
+	editingMoves bool
+	moveRound    int
+	moveNote     bool // Typing the note rather than the label
+
+	// Chaos: the coach is showing the next interval, hidden by default
+	peekNext bool
 }
 
 // TickMsg is sent every second
 type TickMsg time.Time
 
+// RecordedMsg reports that a finished session was saved to the history,
+// so it can be scored against earlier ones
+type RecordedMsg struct {
+	Session history.Session
+}
+
+// CommandMsg is a remote control command (from MQTT, OSC, ...).
+// Action is one of start, pause, toggle, reset or mode; Arg holds the mode
+// name for the mode action.
+type CommandMsg struct {
+	Action string
+	Arg    string
+}
+
 // New creates a new app model
-func New(audioPlayer *audio.Player) Model {
+func New(audioPlayer *audio.Player, dispatcher *events.Dispatcher) Model {
 	t := timer.New()
 	t.Mode = timer.ModeClock
 
@@ -58,18 +163,87 @@ func New(audioPlayer *audio.Player) Model {
 		timer:        t,
 		stopwatch:    timer.NewStopwatch(),
 		audio:        audioPlayer,
+		events:       dispatcher,
 		keys:         DefaultKeyMap(),
 		state:        StateRunning,
 		settingField: SettingWork,
 	}
 }
 
+// WithWorkout returns a model that runs the given timer as soon as the
+// program starts instead of showing the clock
+func (m Model) WithWorkout(t *timer.Timer) Model {
+	m.timer = t
+	m.state = StateRunning
+	m.started = false
+	m.autoStart = true
+	return m
+}
+
+// WithSchedule returns a model that shows the clock and starts t exactly at
+// the given time, with 3-2-1 lead-in beeps
+func (m Model) WithSchedule(t *timer.Timer, at time.Time) Model {
+	m.armed = t
+	m.startAt = at.Truncate(time.Second)
+	m.timer.SetMode(timer.ModeClock)
+	m.state = StateRunning
+	return m
+}
+
+// WithPlan returns a model that sits on the clock and arms each class of
+// the day plan in turn
+func (m Model) WithPlan(plan *schedule.Schedule) Model {
+	m.plan = plan
+	m.timer.SetMode(timer.ModeClock)
+	m.state = StateRunning
+	return m
+}
+
+// WithExport lets the finished screen export the session last recorded in
+// historyPath to dir in format (csv, json, fit or tcx)
+func (m Model) WithExport(historyPath, dir, format string) Model {
+	if dir == "" {
+		dir = "."
+	}
+	if format == "" {
+		format = "csv"
+	}
+	m.historyPath = historyPath
+	m.exportDir = dir
+	m.exportFormat = format
+	return m
+}
+
+// WithSession returns a model that periodically saves its state to path.
+// If pending is set the user is first asked whether to resume it.
+func (m Model) WithSession(path string, pending *session.Snapshot) Model {
+	m.sessionPath = path
+	if pending != nil {
+		m.resume = pending
+		m.state = StateResume
+	}
+	return m
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(tickCmd(), tea.EnterAltScreen)
+	cmds := []tea.Cmd{m.tickCmd(), tea.EnterAltScreen}
+	if m.autoStart {
+		cmds = append(cmds, func() tea.Msg {
+			return CommandMsg{Action: "start"}
+		})
+	}
+	return tea.Batch(cmds...)
 }
 
-func tickCmd() tea.Cmd {
+func (m Model) tickCmd() tea.Cmd {
+	// Line ticks up with the wall clock so a scheduled start lands exactly
+	// on the second
+	if m.armed != nil {
+		return tea.Every(time.Second, func(t time.Time) tea.Msg {
+			return TickMsg(t)
+		})
+	}
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
 		return TickMsg(t)
 	})
@@ -84,11 +258,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case TickMsg:
+		if m.board != nil {
+			m.refreshBoard()
+		}
+		m.handlePlan(time.Time(msg))
+		if m.armed != nil && m.handleSchedule(time.Time(msg)) {
+			// The workout starts on this tick; its first second ends on the next
+			m.stopwatch.Tick()
+			return m, m.tickCmd()
+		}
 		m.handleTick()
-		return m, tickCmd()
+		return m, m.tickCmd()
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+
+	case CommandMsg:
+		m.handleCommand(msg)
+		return m, nil
+
+	case RecordedMsg:
+		if m.state == StateFinished {
+			m.afterFinish(msg.Session)
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -98,6 +291,11 @@ func (m *Model) handleTick() {
 	// Always tick the stopwatch (it runs independently)
 	m.stopwatch.Tick()
 
+	m.ticks++
+	if m.ticks%saveInterval == 0 {
+		m.saveSession()
+	}
+
 	// Handle stopwatch mode separately
 	if m.timer.Mode == timer.ModeStopwatch {
 		return
@@ -111,175 +309,239 @@ func (m *Model) handleTick() {
 		return
 	}
 
-	oldPhase := m.timer.Phase
-	oldRound := m.timer.Round
-
-	// Track time remaining before tick for countdown beeps
-	var remaining time.Duration
-	switch m.timer.Mode {
-	case timer.ModeEMOM:
-		remaining = time.Minute - m.timer.Elapsed
-	case timer.ModeTabata, timer.ModeCustom:
-		if m.timer.Phase == timer.PhaseWork {
-			remaining = m.timer.WorkDuration - m.timer.Elapsed
-		} else {
-			remaining = m.timer.RestDuration - m.timer.Elapsed
-		}
-	case timer.ModeAMRAP:
-		remaining = m.timer.Duration - m.timer.Elapsed
-	}
-
 	// Handle countdown beeps (3, 2, 1)
-	secs := int(remaining.Seconds())
-	if secs <= 3 && secs > 0 && secs != m.lastCountdownBeep {
+	secs := m.timer.CountdownBeep()
+	if secs > 0 && secs != m.lastCountdownBeep {
 		m.audio.PlayCountdown(secs)
 		m.lastCountdownBeep = secs
+
+		e := events.New(events.KindCountdown, m.timer)
+		e.Countdown = secs
+		m.events.Emit(e)
 	}
-	if secs > 3 {
+	if secs == 0 {
 		m.lastCountdownBeep = 0
 	}
 
 	// Advance timer
-	m.timer.Tick()
+	step := m.timer.Advance()
+	if step.Finished {
+		m.state = StateFinished
+		m.result = nil
+	}
+	if step.Finished || step.PhaseChanged {
+		m.saveSession()
+	}
 
-	// Handle interval transitions
-	switch m.timer.Mode {
-	case timer.ModeEMOM:
-		if m.timer.Elapsed >= time.Minute {
-			m.timer.Elapsed = 0
-			m.timer.Round++
-			m.audio.PlayIntervalChange(true)
-			if m.timer.Round > m.timer.TotalRounds {
-				m.timer.Running = false
-				m.state = StateFinished
-				m.audio.PlayFinish()
-			}
-		}
-	case timer.ModeTabata, timer.ModeCustom:
-		var currentDuration time.Duration
-		if m.timer.Phase == timer.PhaseWork {
-			currentDuration = m.timer.WorkDuration
-		} else {
-			currentDuration = m.timer.RestDuration
-		}
-
-		if m.timer.Elapsed >= currentDuration {
-			m.timer.Elapsed = 0
-			if m.timer.Phase == timer.PhaseWork {
-				m.timer.Phase = timer.PhaseRest
-				m.audio.PlayIntervalChange(false)
-			} else {
-				m.timer.Phase = timer.PhaseWork
-				m.timer.Round++
-				m.audio.PlayIntervalChange(true)
-			}
-			if m.timer.Round > m.timer.TotalRounds {
-				m.timer.Running = false
-				m.state = StateFinished
-				m.audio.PlayFinish()
-			}
-		}
-	case timer.ModeAMRAP:
-		if m.timer.Elapsed >= m.timer.Duration {
-			m.timer.Running = false
-			m.state = StateFinished
-			m.audio.PlayFinish()
+	// Play the transition sounds and notify listeners
+	cue.Step(m.timer, step, m.audio, m.events)
+}
+
+// handlePlan arms the next class of the day plan while the clock is idle,
+// and returns to the clock a while after a class has finished
+func (m *Model) handlePlan(now time.Time) {
+	if m.plan == nil {
+		return
+	}
+
+	if m.classTimer != nil && m.timer == m.classTimer && m.state == StateFinished {
+		if m.finishedAt.IsZero() {
+			m.finishedAt = now
+		} else if now.Sub(m.finishedAt) >= classFinishedHold {
+			m.switchMode(timer.ModeClock)
 		}
+		return
 	}
 
-	// Play sound on phase/round change
-	if oldPhase != m.timer.Phase || oldRound != m.timer.Round {
-		// Already handled above
+	if m.armed != nil || m.timer.Mode != timer.ModeClock {
+		return
 	}
+
+	after := now
+	if m.planAfter.After(after) {
+		after = m.planAfter
+	}
+	class, ok := m.plan.Next(after)
+	if !ok {
+		return
+	}
+	t, err := class.Workout.Timer()
+	if err != nil {
+		// Schedules check their workouts when loading, but don't retry a
+		// bad class on every tick if one slips through
+		m.planAfter = class.Start
+		return
+	}
+
+	m.armed = t
+	m.startAt = class.Start
+	m.armedClass = class.Label()
+	m.classTimer = t
+	m.finishedAt = time.Time{}
+}
+
+// handleSchedule plays the lead-in beeps for an armed workout and starts it
+// once its time has come, reporting whether it started
+func (m *Model) handleSchedule(now time.Time) bool {
+	left := int(m.startAt.Sub(now).Round(time.Second) / time.Second)
+	if left > 0 {
+		if left <= 3 && left != m.lastCountdownBeep {
+			m.audio.PlayCountdown(left)
+			m.lastCountdownBeep = left
+
+			e := events.New(events.KindCountdown, m.armed)
+			e.Phase = "get ready"
+			e.Countdown = left
+			m.events.Emit(e)
+		}
+		return false
+	}
+
+	m.timer = m.armed
+	m.armed = nil
+	m.armedClass = ""
+	m.lastCountdownBeep = 0
+	m.state = StateRunning
+	m.started = false
+	m.audio.PlayIntervalChange(true)
+	m.toggleRunning()
+	return true
+}
+
+// emit sends an event describing the current timer state
+func (m *Model) emit(kind events.Kind) {
+	m.events.Emit(events.New(kind, m.timer))
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Typing movements, where every letter is text
+	if m.editingMoves && msg.Type != tea.KeyCtrlC {
+		m.handleMovementKey(msg)
+		return m, nil
+	}
+
 	// Quit always works
 	if m.keys.Quit.Matches(msg) {
+		// Quitting on purpose is not an interruption
+		if m.sessionPath != "" {
+			session.Clear(m.sessionPath)
+		}
 		return m, tea.Quit
 	}
 
+	m.notice = ""
+
+	// Statistics screen
+	if m.stats != nil {
+		if m.keys.Stats.Matches(msg) || m.keys.Discard.Matches(msg) {
+			m.stats = nil
+		}
+		return m, nil
+	}
+
+	// Leaderboard
+	if m.board != nil {
+		m.handleBoardKey(msg)
+		return m, nil
+	}
+
+	// Heat results
+	if m.heatResults {
+		m.handleResultsKey(msg)
+		return m, nil
+	}
+
+	// Score entry on the finished screen
+	if m.scoring {
+		m.handleScoreKey(msg)
+		return m, nil
+	}
+
+	// Benchmark library
+	if m.picking {
+		m.handlePickKey(msg)
+		return m, nil
+	}
+
+	// Handle resume prompt keys
+	if m.state == StateResume {
+		m.handleResumeKey(msg)
+		return m, nil
+	}
+
 	// Handle setup mode keys
 	if m.state == StateSetup {
 		return m.handleSetupKey(msg)
 	}
 
+	// Heat lanes
+	if m.heat != nil && m.handleHeatKey(msg) {
+		return m, nil
+	}
+
 	// Mode switching
 	if m.keys.ModeClock.Matches(msg) {
-		m.timer.SetMode(timer.ModeClock)
-		m.state = StateRunning
+		m.switchMode(timer.ModeClock)
 		return m, nil
 	}
 	if m.keys.ModeEMOM.Matches(msg) {
-		m.timer.SetMode(timer.ModeEMOM)
-		m.state = StateSetup
-		m.settingField = SettingRounds
+		m.switchMode(timer.ModeEMOM)
 		return m, nil
 	}
 	if m.keys.ModeTabata.Matches(msg) {
-		m.timer.SetMode(timer.ModeTabata)
-		m.state = StateSetup
-		m.settingField = SettingWork
+		m.switchMode(timer.ModeTabata)
 		return m, nil
 	}
 	if m.keys.ModeAMRAP.Matches(msg) {
-		m.timer.SetMode(timer.ModeAMRAP)
-		m.state = StateSetup
-		m.settingField = SettingDuration
+		m.switchMode(timer.ModeAMRAP)
 		return m, nil
 	}
 	if m.keys.ModeCustom.Matches(msg) {
-		m.timer.SetMode(timer.ModeCustom)
-		m.state = StateSetup
-		m.settingField = SettingWork
+		m.switchMode(timer.ModeCustom)
 		return m, nil
 	}
 	if m.keys.ModeStopwatch.Matches(msg) {
-		m.timer.SetMode(timer.ModeStopwatch)
-		m.state = StateRunning
+		m.switchMode(timer.ModeStopwatch)
+		return m, nil
+	}
+	if m.keys.ModeForTime.Matches(msg) {
+		m.switchMode(timer.ModeForTime)
+		return m, nil
+	}
+	if m.keys.ModeCircuit.Matches(msg) {
+		m.switchMode(timer.ModeCircuit)
+		return m, nil
+	}
+	if m.keys.ModeDeathBy.Matches(msg) {
+		m.switchMode(timer.ModeDeathBy)
+		return m, nil
+	}
+	if m.keys.ModeChaos.Matches(msg) {
+		m.switchMode(timer.ModeChaos)
 		return m, nil
 	}
 
 	// Stopwatch controls (work from any mode)
 	if m.keys.StopwatchToggle.Matches(msg) {
 		m.stopwatch.Toggle()
+		m.saveSession()
 		return m, nil
 	}
 	if m.keys.StopwatchReset.Matches(msg) {
 		m.stopwatch.Reset()
+		m.saveSession()
 		return m, nil
 	}
 
 	// Start/pause
 	if m.keys.StartPause.Matches(msg) {
-		// In stopwatch mode, space controls the stopwatch
-		if m.timer.Mode == timer.ModeStopwatch {
-			m.stopwatch.Toggle()
-			return m, nil
-		}
-		if m.state == StateFinished {
-			m.timer.Reset()
-			m.state = StateRunning
-		}
-		m.timer.Toggle()
-		if m.timer.Running {
-			m.state = StateRunning
-		} else {
-			m.state = StatePaused
-		}
+		m.toggleRunning()
 		return m, nil
 	}
 
 	// Reset
 	if m.keys.Reset.Matches(msg) {
-		// In stopwatch mode, R resets the stopwatch
-		if m.timer.Mode == timer.ModeStopwatch {
-			m.stopwatch.Reset()
-			return m, nil
-		}
-		m.timer.Reset()
-		m.state = StateRunning
-		m.lastCountdownBeep = 0
+		m.reset()
 		return m, nil
 	}
 
@@ -289,13 +551,267 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Export the finished session
+	if m.keys.Export.Matches(msg) && m.state == StateFinished && m.historyPath != "" {
+		m.exportSession()
+		return m, nil
+	}
+
+	// For Time: the athlete is done
+	if m.keys.Enter.Matches(msg) && m.timer.Mode == timer.ModeForTime && m.started && m.state != StateFinished {
+		m.finishNow()
+		return m, nil
+	}
+
+	// Death by: the athlete failed this minute
+	if m.keys.Enter.Matches(msg) && m.timer.Mode == timer.ModeDeathBy && m.started && m.state != StateFinished {
+		m.finishNow()
+		return m, nil
+	}
+
+	// Chaos: show or hide the next interval for the coach
+	if m.keys.PeekNext.Matches(msg) && m.timer.Mode == timer.ModeChaos {
+		m.peekNext = !m.peekNext
+		return m, nil
+	}
+
+	// Choose a benchmark
+	if m.keys.Benchmarks.Matches(msg) {
+		m.openLibrary()
+		return m, nil
+	}
+
+	// Log a score for the finished session
+	if m.keys.Enter.Matches(msg) && m.state == StateFinished && m.historyPath != "" {
+		if _, ok := m.scoreKind(); ok {
+			m.scoring = true
+		}
+		return m, nil
+	}
+
+	// Statistics
+	if m.keys.Stats.Matches(msg) && m.historyPath != "" {
+		m.openStats()
+		return m, nil
+	}
+
+	// Competition leaderboard
+	if m.keys.Leaderboard.Matches(msg) && m.boardPath != "" {
+		m.openBoard()
+		return m, nil
+	}
+
 	return m, nil
 }
 
+// exportSession writes the most recently recorded session to the export
+// directory
+func (m *Model) exportSession() {
+	sessions, err := history.Load(m.historyPath)
+	if err == nil && len(sessions) == 0 {
+		err = fmt.Errorf("no recorded session")
+	}
+	if err != nil {
+		m.notice = "Export failed: " + err.Error()
+		return
+	}
+
+	path, err := export.SaveSession(m.exportDir, m.exportFormat, sessions[len(sessions)-1])
+	if err != nil {
+		m.notice = "Export failed: " + err.Error()
+		return
+	}
+	m.notice = "Exported to " + path
+}
+
+func (m *Model) handleCommand(msg CommandMsg) {
+	running := m.timer.Running
+	if m.timer.Mode == timer.ModeStopwatch {
+		running = m.stopwatch.Running
+	}
+
+	switch msg.Action {
+	case "start":
+		if m.state == StateSetup {
+			m.state = StateRunning
+		}
+		if !running {
+			m.toggleRunning()
+		}
+	case "pause":
+		if running {
+			m.toggleRunning()
+		}
+	case "toggle":
+		if m.state != StateSetup {
+			m.toggleRunning()
+		}
+	case "reset":
+		m.reset()
+	case "mode":
+		if mode, ok := timer.ParseMode(msg.Arg); ok {
+			// Remote mode changes skip setup and use the mode defaults
+			m.switchMode(mode)
+			m.state = StateRunning
+		}
+	}
+}
+
+// switchMode changes the timer mode, entering setup for configurable modes
+func (m *Model) switchMode(mode timer.Mode) {
+	m.cancelSchedule()
+	m.scoring = false
+	m.scoreLane = 0
+	m.timer.SetMode(mode)
+	m.started = false
+	m.state = StateRunning
+
+	switch mode {
+	case timer.ModeEMOM:
+		m.state = StateSetup
+		m.settingField = SettingRounds
+	case timer.ModeTabata, timer.ModeCustom, timer.ModeCircuit:
+		m.state = StateSetup
+		m.settingField = SettingWork
+	case timer.ModeAMRAP, timer.ModeForTime:
+		m.state = StateSetup
+		m.settingField = SettingDuration
+	case timer.ModeDeathBy:
+		m.state = StateSetup
+		m.settingField = SettingReps
+	case timer.ModeChaos:
+		m.state = StateSetup
+		m.settingField = SettingDuration
+	}
+	m.peekNext = false
+	m.benchmark = nil
+	m.result = nil
+	if !m.heatModes() {
+		m.heat = nil
+	}
+	m.saveSession()
+}
+
+// toggleRunning starts or pauses the current timer
+func (m *Model) toggleRunning() {
+	// In stopwatch mode, space controls the stopwatch
+	if m.timer.Mode == timer.ModeStopwatch {
+		m.stopwatch.Toggle()
+		m.saveSession()
+		return
+	}
+	if m.state == StateFinished {
+		m.timer.Reset()
+		m.state = StateRunning
+		m.started = false
+	}
+	m.timer.Toggle()
+	if m.timer.Running {
+		m.state = StateRunning
+		if m.started {
+			m.emit(events.KindResume)
+		} else {
+			m.started = true
+			m.heatStart()
+			m.emit(events.KindStart)
+		}
+	} else {
+		m.state = StatePaused
+		m.emit(events.KindPause)
+	}
+	m.saveSession()
+}
+
+// reset returns the current timer to its initial state
+func (m *Model) reset() {
+	// In stopwatch mode, R resets the stopwatch
+	if m.timer.Mode == timer.ModeStopwatch {
+		m.stopwatch.Reset()
+		m.saveSession()
+		return
+	}
+	m.timer.Reset()
+	m.state = StateRunning
+	m.lastCountdownBeep = 0
+	m.started = false
+	if m.heat != nil {
+		m.heat.Reset()
+	}
+	m.cancelSchedule()
+	m.saveSession()
+}
+
+// cancelSchedule disarms a scheduled workout. A class from the day plan is
+// skipped rather than armed again.
+func (m *Model) cancelSchedule() {
+	if m.armed != nil && m.armedClass != "" {
+		m.planAfter = m.startAt
+	}
+	m.armed = nil
+	m.armedClass = ""
+}
+
+// saveSession snapshots the timers so an interrupted session can be
+// resumed, or removes the snapshot when there is nothing worth resuming
+func (m *Model) saveSession() {
+	// Leave the pending snapshot alone until the user decides
+	if m.sessionPath == "" || m.state == StateResume {
+		return
+	}
+
+	inWorkout := m.started && m.state != StateFinished
+	if !inWorkout && !m.stopwatch.Running && m.stopwatch.Elapsed == 0 {
+		session.Clear(m.sessionPath)
+		return
+	}
+	session.Save(m.sessionPath, session.New(m.timer, m.stopwatch, inWorkout))
+}
+
+func (m *Model) handleResumeKey(msg tea.KeyMsg) {
+	switch {
+	case m.keys.Enter.Matches(msg):
+		// Pick up where the session would be had it never stopped
+		m.resume.CatchUp(time.Now())
+		m.restore(m.resume)
+	case m.keys.ResumePaused.Matches(msg):
+		m.resume.Timer.Running = false
+		m.resume.Stopwatch.Running = false
+		m.restore(m.resume)
+	case m.keys.Discard.Matches(msg):
+		m.resume = nil
+		m.state = StateRunning
+		session.Clear(m.sessionPath)
+	}
+}
+
+// restore replaces the timers with a snapshot
+func (m *Model) restore(snap *session.Snapshot) {
+	m.timer = snap.Timer
+	m.stopwatch = snap.Stopwatch
+	m.resume = nil
+	m.started = snap.Started
+
+	switch {
+	case m.timer.IsFinished():
+		m.timer.Running = false
+		m.state = StateFinished
+	case m.timer.Running || !m.started:
+		m.state = StateRunning
+	default:
+		m.state = StatePaused
+	}
+
+	if m.timer.Running {
+		m.emit(events.KindResume)
+	}
+	m.saveSession()
+}
+
 func (m Model) handleSetupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case m.keys.Enter.Matches(msg):
 		m.state = StateRunning
+		m.setupHeat()
 		return m, nil
 
 	case m.keys.Up.Matches(msg):
@@ -313,6 +829,10 @@ func (m Model) handleSetupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case m.keys.Right.Matches(msg):
 		m.nextSetting()
 		return m, nil
+
+	case m.keys.Movements.Matches(msg) && m.movementModes():
+		m.openMovements()
+		return m, nil
 	}
 
 	return m, nil
@@ -337,6 +857,14 @@ func (m *Model) adjustSetting(delta int) {
 			m.timer.RestDuration = 5 * time.Minute
 		}
 	case SettingRounds:
+		if m.timer.Mode == timer.ModeCircuit {
+			m.timer.SetLaps(max(1, min(m.timer.Laps()+delta, 20)))
+			break
+		}
+		if m.timer.HasSets() {
+			m.timer.TotalRounds = max(1, min(m.timer.RoundsPerSet()+delta, 99)) * m.timer.Sets
+			break
+		}
 		m.timer.TotalRounds += delta
 		if m.timer.TotalRounds < 1 {
 			m.timer.TotalRounds = 1
@@ -346,39 +874,133 @@ func (m *Model) adjustSetting(delta int) {
 		}
 	case SettingDuration:
 		m.timer.Duration += time.Duration(delta) * time.Minute
-		if m.timer.Duration < time.Minute {
-			m.timer.Duration = time.Minute
+		// For Time may run without a cap
+		minimum := time.Minute
+		if m.timer.Mode == timer.ModeForTime {
+			minimum = 0
+		}
+		if m.timer.Duration < minimum {
+			m.timer.Duration = minimum
 		}
 		if m.timer.Duration > 60*time.Minute {
 			m.timer.Duration = 60 * time.Minute
 		}
+	case SettingLanes:
+		m.heatLanes = max(0, min(m.heatLanes+delta, heat.MaxLanes))
+	case SettingProgression:
+		m.adjustProgression(delta)
+	case SettingStep:
+		if m.timer.Mode == timer.ModeDeathBy {
+			m.timer.RepStep = max(1, min(m.timer.RepStep+delta, maxRepStep))
+			break
+		}
+		m.adjustStep(delta)
+	case SettingReps:
+		m.timer.StartReps = max(1, min(m.timer.StartReps+delta, maxStartReps))
+	case SettingSets:
+		m.timer.SetSets(max(1, min(m.timer.Sets+delta, maxSets)))
+	case SettingSetRest:
+		rest := m.timer.SetRest + time.Duration(delta*15)*time.Second
+		m.timer.SetRest = max(15*time.Second, min(rest, 10*time.Minute))
+	case SettingStations:
+		if m.timer.Mode == timer.ModeEMOM {
+			m.setRotation(m.timer.Rotation + delta)
+			break
+		}
+		m.setStationCount(len(m.timer.Stations) + delta)
+	case SettingWorkMax, SettingRestMax, SettingSeed:
+		m.adjustChaos(delta)
+	}
+	if m.timer.Mode == timer.ModeChaos {
+		m.keepRanges()
+		m.timer.Draw()
 	}
 }
 
 func (m *Model) nextSetting() {
 	switch m.timer.Mode {
+	case timer.ModeEMOM:
+		switch m.settingField {
+		case SettingWork:
+			m.settingField = SettingRounds
+		case SettingRounds:
+			m.settingField = SettingStations
+		default:
+			m.settingField = SettingWork
+		}
 	case timer.ModeTabata, timer.ModeCustom:
+		m.settingField = m.intervalField(1)
+	case timer.ModeChaos:
+		m.settingField = m.chaosField(1)
+	case timer.ModeDeathBy:
+		switch m.settingField {
+		case SettingReps:
+			m.settingField = SettingStep
+		case SettingStep:
+			m.settingField = SettingRounds
+		default:
+			m.settingField = SettingReps
+		}
+	case timer.ModeCircuit:
 		switch m.settingField {
 		case SettingWork:
 			m.settingField = SettingRest
 		case SettingRest:
+			m.settingField = SettingStations
+		case SettingStations:
 			m.settingField = SettingRounds
 		case SettingRounds:
 			m.settingField = SettingWork
 		}
+	case timer.ModeAMRAP, timer.ModeForTime:
+		if m.settingField == SettingDuration {
+			m.settingField = SettingLanes
+		} else {
+			m.settingField = SettingDuration
+		}
 	}
 }
 
 func (m *Model) prevSetting() {
 	switch m.timer.Mode {
+	case timer.ModeEMOM:
+		switch m.settingField {
+		case SettingWork:
+			m.settingField = SettingStations
+		case SettingRounds:
+			m.settingField = SettingWork
+		default:
+			m.settingField = SettingRounds
+		}
 	case timer.ModeTabata, timer.ModeCustom:
+		m.settingField = m.intervalField(-1)
+	case timer.ModeChaos:
+		m.settingField = m.chaosField(-1)
+	case timer.ModeDeathBy:
+		switch m.settingField {
+		case SettingReps:
+			m.settingField = SettingRounds
+		case SettingStep:
+			m.settingField = SettingReps
+		default:
+			m.settingField = SettingStep
+		}
+	case timer.ModeCircuit:
 		switch m.settingField {
 		case SettingWork:
 			m.settingField = SettingRounds
 		case SettingRest:
 			m.settingField = SettingWork
-		case SettingRounds:
+		case SettingStations:
 			m.settingField = SettingRest
+		case SettingRounds:
+			m.settingField = SettingStations
+		}
+	case timer.ModeAMRAP, timer.ModeForTime:
+		if m.settingField == SettingDuration {
+			m.settingField = SettingLanes
+		} else {
+			m.settingField = SettingDuration
 		}
 	}
 }
@@ -393,8 +1015,30 @@ func (m Model) View() string {
 
 	switch m.state {
 	case StateSetup:
+		if m.editingMoves {
+			content = m.renderMovementEditor()
+			break
+		}
 		content = m.renderSetup()
+	case StateResume:
+		content = m.renderResume()
 	default:
+		if m.stats != nil {
+			content = m.renderStats()
+			break
+		}
+		if m.board != nil {
+			content = m.renderBoard()
+			break
+		}
+		if m.picking {
+			content = m.renderLibrary()
+			break
+		}
+		if m.heatResults {
+			content = m.renderHeatResults()
+			break
+		}
 		content = m.renderTimer()
 	}
 
@@ -408,6 +1052,10 @@ func (m Model) renderTimer() string {
 	title := TitleStyle.Render(fmt.Sprintf("MODE: %s", m.timer.ModeName()))
 	s += title + "\n\n"
 
+	if m.benchmark != nil {
+		s += m.renderBenchmarkHeader()
+	}
+
 	// Time display
 	var timeStr string
 	var color lipgloss.Color
@@ -417,8 +1065,8 @@ func (m Model) renderTimer() string {
 		now := time.Now()
 		timeStr = now.Format("15:04:05")
 		color = ColorNeutral
-	case timer.ModeEMOM:
-		remaining := time.Minute - m.timer.Elapsed
+	case timer.ModeEMOM, timer.ModeDeathBy:
+		remaining := m.timer.WorkDuration - m.timer.Elapsed
 		if remaining < 0 {
 			remaining = 0
 		}
@@ -426,16 +1074,13 @@ func (m Model) renderTimer() string {
 		secs := int(remaining.Seconds()) % 60
 		timeStr = fmt.Sprintf("%02d:%02d", mins, secs)
 		color = ColorWork
-	case timer.ModeTabata, timer.ModeCustom:
-		var currentDuration time.Duration
+	case timer.ModeTabata, timer.ModeCustom, timer.ModeCircuit, timer.ModeChaos:
 		if m.timer.Phase == timer.PhaseWork {
-			currentDuration = m.timer.WorkDuration
 			color = ColorWork
 		} else {
-			currentDuration = m.timer.RestDuration
 			color = ColorRest
 		}
-		remaining := currentDuration - m.timer.Elapsed
+		remaining := m.timer.IntervalDuration() - m.timer.Elapsed
 		if remaining < 0 {
 			remaining = 0
 		}
@@ -451,6 +1096,9 @@ func (m Model) renderTimer() string {
 		secs := int(remaining.Seconds()) % 60
 		timeStr = fmt.Sprintf("%02d:%02d", mins, secs)
 		color = ColorWork
+	case timer.ModeForTime:
+		timeStr = formatCountdown(m.timer.Elapsed)
+		color = ColorWork
 	case timer.ModeStopwatch:
 		timeStr = m.stopwatch.Format()
 		if m.stopwatch.Running {
@@ -467,10 +1115,38 @@ func (m Model) renderTimer() string {
 		color = ColorFinished
 	}
 
-	s += RenderBigTime(timeStr, color)
+	// Lanes or stations beside the clock
+	var side string
+	switch {
+	case m.heat != nil:
+		side = m.renderLanes()
+	case m.timer.Mode == timer.ModeCircuit:
+		side = m.renderStations()
+	}
+	if side != "" {
+		s += lipgloss.JoinHorizontal(lipgloss.Center, strings.TrimSuffix(RenderBigTime(timeStr, color), "\n"), "    ", side) + "\n"
+	} else {
+		s += RenderBigTime(timeStr, color)
+	}
 
 	// Phase indicator
-	if m.timer.Mode == timer.ModeTabata || m.timer.Mode == timer.ModeCustom {
+	switch m.timer.Mode {
+	case timer.ModeTabata, timer.ModeCustom:
+		switch {
+		case m.timer.Phase == timer.PhaseWork:
+			s += PhaseWorkStyle.Render("WORK") + "\n"
+		case m.timer.EndsSet(m.timer.Round):
+			s += PhaseRestStyle.Render("SET REST") + "\n"
+		default:
+			s += PhaseRestStyle.Render("REST") + "\n"
+		}
+	case timer.ModeCircuit:
+		if m.timer.Phase == timer.PhaseWork {
+			s += PhaseWorkStyle.Render("WORK") + "\n"
+		} else {
+			s += PhaseRestStyle.Render("MOVE") + "\n"
+		}
+	case timer.ModeChaos:
 		if m.timer.Phase == timer.PhaseWork {
 			s += PhaseWorkStyle.Render("WORK") + "\n"
 		} else {
@@ -478,12 +1154,58 @@ func (m Model) renderTimer() string {
 		}
 	}
 
+	// What to do this round
+	if m.timer.HasMovements() {
+		s += m.renderMovement()
+	}
+
+	// For Time cap, and the key to stop the clock
+	if m.timer.Mode == timer.ModeForTime && m.state != StateFinished {
+		line := "No time cap"
+		if m.timer.Duration > 0 {
+			line = "Time cap " + formatCountdown(m.timer.Duration)
+		}
+		if m.started {
+			line += "  [Enter] Done"
+		}
+		s += RoundStyle.Render(line) + "\n"
+	}
+
 	// Round counter
-	if m.timer.Mode != timer.ModeClock && m.timer.Mode != timer.ModeAMRAP && m.timer.Mode != timer.ModeStopwatch {
+	if m.timer.Mode == timer.ModeDeathBy {
+		s += m.renderRepTarget()
+	} else if m.timer.Mode == timer.ModeChaos {
+		s += m.renderChaosProgress()
+	} else if m.timer.Mode == timer.ModeCircuit {
+		s += RoundStyle.Render(m.circuitProgress()) + "\n"
+	} else if m.timer.Rotates() {
+		s += RoundStyle.Render(m.rotationProgress()) + "\n"
+	} else if m.timer.HasSets() {
+		s += RoundStyle.Render(m.setsProgress()) + "\n"
+	} else if m.timer.HasRounds() {
 		roundStr := fmt.Sprintf("Round %d of %d", m.timer.Round, m.timer.TotalRounds)
 		s += RoundStyle.Render(roundStr) + "\n"
 	}
 
+	// Who is working
+	if m.timer.Relay() {
+		s += m.renderRelay()
+	}
+
+	// Scheduled start
+	if m.armed != nil {
+		left := time.Until(m.startAt).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+		next := fmt.Sprintf("Next: %s at %s", m.armed.Summary(), m.startAt.Format("15:04:05"))
+		if m.armedClass != "" {
+			next = "Next class: " + m.armedClass
+		}
+		s += PhaseWorkStyle.Render(next) + "\n"
+		s += RoundStyle.Render("Starts in "+formatCountdown(left)) + "\n"
+	}
+
 	// Stopwatch indicator (when running in background)
 	if m.timer.Mode != timer.ModeStopwatch && (m.stopwatch.Running || m.stopwatch.Elapsed > 0) {
 		swStatus := "paused"
@@ -502,6 +1224,24 @@ func (m Model) renderTimer() string {
 	}
 	if m.state == StateFinished && m.timer.Mode != timer.ModeStopwatch {
 		s += "\n" + lipgloss.NewStyle().Foreground(ColorFinished).Bold(true).Render("FINISHED!")
+		if m.result != nil {
+			s += "\n" + m.renderResult()
+		}
+		if m.timer.Relay() {
+			s += "\n" + m.renderWorkTotals()
+		}
+		switch {
+		case m.scoring:
+			s += "\n" + m.renderScoreEntry()
+		case m.notice != "":
+			s += "\n" + RoundStyle.Render(m.notice)
+		case m.historyPath != "":
+			help := m.keys.Export.Help
+			if _, ok := m.scoreKind(); ok {
+				help = "[Enter] Log score  " + help
+			}
+			s += "\n" + HelpStyle.Render(help)
+		}
 	}
 	// Stopwatch status when viewing stopwatch
 	if m.timer.Mode == timer.ModeStopwatch && !m.stopwatch.Running && m.stopwatch.Elapsed > 0 {
@@ -509,7 +1249,7 @@ func (m Model) renderTimer() string {
 	}
 
 	// Mode selector
-	modes := "[1]Clock  [2]EMOM  [3]Tabata  [4]AMRAP  [5]Custom  [6]Stopwatch"
+	modes := "[1]Clock  [2]EMOM  [3]Tabata  [4]AMRAP  [5]Custom  [6]Stopwatch  [7]For Time  [8]Circuit  [9]Death By  [0]Chaos  [B]Benchmarks"
 	s += "\n" + HelpStyle.Render(modes)
 
 	// Help bar
@@ -523,11 +1263,28 @@ func (m Model) renderTimer() string {
 	} else {
 		help = fmt.Sprintf("[Space] Start/Pause  [R] Reset  [W] Stopwatch  [S] Sound: %s  [Q] Quit", soundStatus)
 	}
+	if m.historyPath != "" {
+		help = strings.Replace(help, "[Q] Quit", m.keys.Stats.Help+"  [Q] Quit", 1)
+	}
+	if m.boardPath != "" {
+		help = strings.Replace(help, "[Q] Quit", m.keys.Leaderboard.Help+"  [Q] Quit", 1)
+	}
+	if m.timer.Mode == timer.ModeChaos && m.state != StateFinished {
+		help = strings.Replace(help, "[Q] Quit", m.keys.PeekNext.Help+"  [Q] Quit", 1)
+	}
 	s += "\n" + HelpStyle.Render(help)
 
 	return s
 }
 
+// settingStyle highlights the setting being edited
+func (m Model) settingStyle(field SettingField) lipgloss.Style {
+	if m.settingField == field {
+		return SettingSelectedStyle
+	}
+	return SettingStyle
+}
+
 func (m Model) renderSetup() string {
 	var s string
 
@@ -536,11 +1293,18 @@ func (m Model) renderSetup() string {
 
 	switch m.timer.Mode {
 	case timer.ModeEMOM:
+		everyStyle := SettingStyle
 		roundsStyle := SettingStyle
+		if m.settingField == SettingWork {
+			everyStyle = SettingSelectedStyle
+		}
 		if m.settingField == SettingRounds {
 			roundsStyle = SettingSelectedStyle
 		}
+		everySecs := int(m.timer.WorkDuration.Seconds())
+		s += everyStyle.Render(fmt.Sprintf("Every: %ds", everySecs)) + "\n"
 		s += roundsStyle.Render(fmt.Sprintf("Rounds: %d", m.timer.TotalRounds)) + "\n"
+		s += m.renderRotationSetup()
 
 	case timer.ModeTabata, timer.ModeCustom:
 		workStyle := SettingStyle
@@ -562,7 +1326,24 @@ func (m Model) renderSetup() string {
 
 		s += workStyle.Render(fmt.Sprintf("Work: %ds", workSecs)) + "\n"
 		s += restStyle.Render(fmt.Sprintf("Rest: %ds", restSecs)) + "\n"
-		s += roundsStyle.Render(fmt.Sprintf("Rounds: %d", m.timer.TotalRounds)) + "\n"
+		if m.timer.HasSets() {
+			s += roundsStyle.Render(fmt.Sprintf("Rounds per set: %d", m.timer.RoundsPerSet())) + "\n"
+		} else {
+			s += roundsStyle.Render(fmt.Sprintf("Rounds: %d", m.timer.TotalRounds)) + "\n"
+		}
+		s += m.renderSetsSetup()
+		if m.timer.Mode == timer.ModeCustom {
+			s += m.renderProgressionSetup()
+		}
+
+	case timer.ModeCircuit:
+		s += m.renderCircuitSetup()
+
+	case timer.ModeDeathBy:
+		s += m.renderDeathBySetup()
+
+	case timer.ModeChaos:
+		s += m.renderChaosSetup()
 
 	case timer.ModeAMRAP:
 		durStyle := SettingStyle
@@ -571,11 +1352,82 @@ func (m Model) renderSetup() string {
 		}
 		mins := int(m.timer.Duration.Minutes())
 		s += durStyle.Render(fmt.Sprintf("Duration: %d min", mins)) + "\n"
+
+	case timer.ModeForTime:
+		capStyle := SettingStyle
+		if m.settingField == SettingDuration {
+			capStyle = SettingSelectedStyle
+		}
+		timeCap := "none"
+		if m.timer.Duration > 0 {
+			timeCap = fmt.Sprintf("%d min", int(m.timer.Duration.Minutes()))
+		}
+		s += capStyle.Render("Time cap: "+timeCap) + "\n"
+	}
+
+	if m.heatModes() {
+		lanesStyle := SettingStyle
+		if m.settingField == SettingLanes {
+			lanesStyle = SettingSelectedStyle
+		}
+		lanes := "off"
+		if m.heatLanes > 0 {
+			lanes = fmt.Sprintf("%d", m.heatLanes)
+		}
+		s += lanesStyle.Render("Heat lanes: "+lanes) + "\n"
+	}
+
+	if m.movementModes() {
+		s += m.renderMovementSetup()
 	}
 
 	s += "\n"
 	help := "[Up/Down] Adjust  [Left/Right] Switch  [Enter] Start  [Q] Quit"
+	if m.movementModes() {
+		help = "[Up/Down] Adjust  [Left/Right] Switch  " + m.keys.Movements.Help + "  [Enter] Start  [Q] Quit"
+	}
+	s += HelpStyle.Render(help)
+
+	return s
+}
+
+func (m Model) renderResume() string {
+	var s string
+
+	snap := m.resume
+	s += TitleStyle.Render("RESUME INTERRUPTED SESSION?") + "\n\n"
+
+	t := snap.Timer
+	if t.Mode != timer.ModeClock && t.Mode != timer.ModeStopwatch {
+		line := t.ModeName()
+		if t.HasRounds() {
+			line += fmt.Sprintf("  %s  Round %d of %d", t.PhaseName(), min(t.Round, t.TotalRounds), t.TotalRounds)
+		}
+		remaining := t.TimeRemaining()
+		line += fmt.Sprintf("  %02d:%02d left", int(remaining.Minutes()), int(remaining.Seconds())%60)
+		s += SettingStyle.Render(line) + "\n"
+	}
+	if snap.Stopwatch.Elapsed > 0 {
+		s += SettingStyle.Render("Stopwatch: "+snap.Stopwatch.Format()) + "\n"
+	}
+
+	missed := time.Duration(snap.Missed(time.Now())) * time.Second
+	s += RoundStyle.Render(fmt.Sprintf("Interrupted %s ago", missed)) + "\n"
+
+	help := "[Enter] Resume (count time since)  [P] Resume paused  [N] Discard  [Q] Quit"
 	s += HelpStyle.Render(help)
 
 	return s
 }
+
+// formatCountdown formats a duration as MM:SS, or H:MM:SS past an hour
+func formatCountdown(d time.Duration) string {
+	total := int(d.Seconds())
+	hours := total / 3600
+	mins := (total % 3600) / 60
+	secs := total % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, mins, secs)
+	}
+	return fmt.Sprintf("%02d:%02d", mins, secs)
+}
@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/benchmark"
+	"gymtimer/internal/events"
+	"gymtimer/internal/history"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+)
+
+// finishResult is how a finished workout compares with earlier sessions
+type finishResult struct {
+	start time.Time    // Start of the session in the history
+	score *score.Score // Once logged
+	best  *score.Score // Best earlier result, if any
+	pr    bool
+}
+
+// WithBenchmark returns a model that scores its workout as the given
+// benchmark. Use it with WithWorkout.
+func (m Model) WithBenchmark(b benchmark.Benchmark) Model {
+	m.benchmark = &b
+	m.best = m.previousBest(b.Name(), b.Scoring, time.Time{})
+	return m
+}
+
+// openLibrary shows the benchmark picker
+func (m *Model) openLibrary() {
+	lib, err := benchmark.Library(benchmark.Dir())
+	if err != nil {
+		m.notice = "Custom benchmarks: " + err.Error()
+		lib = benchmark.Builtin
+	}
+	m.library = lib
+	m.pick = 0
+	m.picking = true
+}
+
+func (m *Model) handlePickKey(msg tea.KeyMsg) {
+	switch {
+	case m.keys.Up.Matches(msg):
+		m.pick = (m.pick + len(m.library) - 1) % len(m.library)
+	case m.keys.Down.Matches(msg):
+		m.pick = (m.pick + 1) % len(m.library)
+	case m.keys.Enter.Matches(msg):
+		m.loadBenchmark(m.library[m.pick])
+	case m.keys.Benchmarks.Matches(msg), m.keys.Discard.Matches(msg):
+		m.picking = false
+	}
+}
+
+// loadBenchmark makes the benchmark the current workout, ready to start
+func (m *Model) loadBenchmark(b benchmark.Benchmark) {
+	t, err := b.Workout.Timer()
+	if err != nil {
+		m.notice = err.Error()
+		return
+	}
+
+	m.cancelSchedule()
+	m.picking = false
+	m.timer = t
+	m.state = StateRunning
+	m.started = false
+	m.lastCountdownBeep = 0
+	m.result = nil
+	*m = m.WithBenchmark(b)
+	m.saveSession()
+}
+
+// finishNow stops a For Time workout when the athlete is done, or a Death
+// by workout when the athlete fails
+func (m *Model) finishNow() {
+	m.timer.Finish()
+	m.state = StateFinished
+	m.audio.PlayFinish()
+	m.result = nil
+	m.emit(events.KindFinish)
+	m.saveSession()
+}
+
+// afterFinish compares the session just recorded, s, with earlier ones.
+// For Time results are scored straight away, and Death by asks for the reps
+// made in the failed minute.
+func (m *Model) afterFinish(s history.Session) {
+	m.result = nil
+	kind, ok := m.scoreKind()
+	if !ok || m.historyPath == "" {
+		return
+	}
+
+	m.result = &finishResult{
+		start: s.Start,
+		best:  m.previousBest(s.Title(), kind, s.Start),
+	}
+
+	if m.timer.Mode == timer.ModeForTime && m.timer.Done {
+		sc := score.Score{Kind: score.Time, Time: m.timer.Elapsed}
+		if err := history.SetScore(m.historyPath, s.Start, sc); err != nil {
+			m.notice = "Could not save score: " + err.Error()
+			return
+		}
+		m.recordScore(sc)
+	}
+	if m.timer.Mode == timer.ModeDeathBy {
+		m.scoreDeathBy()
+	}
+}
+
+// recordScore notes the finished session's score and whether it beats the
+// previous best
+func (m *Model) recordScore(sc score.Score) {
+	if m.result == nil {
+		return
+	}
+	m.result.score = &sc
+	m.result.pr = m.result.best != nil && sc.Compare(*m.result.best) > 0
+}
+
+// previousBest returns the best recorded result for a workout, ignoring
+// the session that started at exclude
+func (m Model) previousBest(title string, kind score.Kind, exclude time.Time) *score.Score {
+	if m.historyPath == "" {
+		return nil
+	}
+	sessions, err := history.Load(m.historyPath)
+	if err != nil {
+		return nil
+	}
+	var earlier []history.Session
+	for _, s := range sessions {
+		if !s.Start.Equal(exclude) {
+			earlier = append(earlier, s)
+		}
+	}
+	return history.Best(earlier, title, kind)
+}
+
+// renderBenchmarkHeader names the benchmark above the clock
+func (m Model) renderBenchmarkHeader() string {
+	b := m.benchmark
+	s := SettingSelectedStyle.Render(b.Name()) + "\n"
+	if b.Workout.Description != "" {
+		s += SettingStyle.Render(b.Workout.Description) + "\n"
+	}
+	if m.best != nil && m.state != StateFinished {
+		s += RoundStyle.Render("Best: "+m.best.String()) + "\n"
+	}
+	return s + "\n"
+}
+
+// renderResult shows the score against the previous best
+func (m Model) renderResult() string {
+	r := m.result
+	var s string
+	if r.pr {
+		s += lipgloss.NewStyle().Foreground(ColorWork).Bold(true).Render("★ PR! ★") + "\n"
+	}
+	if r.score != nil {
+		s += SettingStyle.Render("Score: "+r.score.String()) + "\n"
+	}
+	switch {
+	case r.best != nil:
+		s += RoundStyle.Render("Previous best: " + r.best.String())
+	case r.score != nil:
+		s += RoundStyle.Render("First recorded result")
+	}
+	return s
+}
+
+func (m Model) renderLibrary() string {
+	var s string
+	s += TitleStyle.Render("BENCHMARKS") + "\n\n"
+
+	for i, b := range m.library {
+		summary := "invalid"
+		if t, err := b.Workout.Timer(); err == nil {
+			summary = t.Summary()
+		}
+		line := fmt.Sprintf("%-22s %-20s %-12s", b.Name(), summary, b.Scoring)
+		if i == m.pick {
+			s += SettingSelectedStyle.Render("> "+line) + "\n"
+		} else {
+			s += SettingStyle.Render("  "+line) + "\n"
+		}
+	}
+
+	if desc := m.library[m.pick].Workout.Description; desc != "" {
+		s += RoundStyle.Render(desc) + "\n"
+	}
+	if m.notice != "" {
+		s += RoundStyle.Render(m.notice) + "\n"
+	}
+	s += HelpStyle.Render("[Up/Down] Choose  [Enter] Load  [B] Back")
+	return s
+}
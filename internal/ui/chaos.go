@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/timer"
+)
+
+// chaosField returns the chaos setting delta places from the current one
+func (m Model) chaosField(delta int) SettingField {
+	fields := []SettingField{SettingDuration, SettingWork, SettingWorkMax, SettingRest, SettingRestMax, SettingSeed}
+	i := 0
+	for j, f := range fields {
+		if f == m.settingField {
+			i = j
+		}
+	}
+	return fields[(i+delta+len(fields))%len(fields)]
+}
+
+// adjustChaos changes the longest work or rest, which can't go below the
+// shortest, or the seed
+func (m *Model) adjustChaos(delta int) {
+	t := m.timer
+	switch m.settingField {
+	case SettingWorkMax:
+		t.WorkMax = max(t.WorkDuration, min(t.WorkMax+time.Duration(delta*5)*time.Second, 5*time.Minute))
+	case SettingRestMax:
+		t.RestMax = max(t.RestDuration, min(t.RestMax+time.Duration(delta*5)*time.Second, 5*time.Minute))
+	case SettingSeed:
+		t.Seed = max(1, t.Seed+int64(delta))
+	}
+}
+
+// keepRanges raises the longest work and rest to the shortest when those
+// pass them
+func (m *Model) keepRanges() {
+	m.timer.WorkMax = max(m.timer.WorkMax, m.timer.WorkDuration)
+	m.timer.RestMax = max(m.timer.RestMax, m.timer.RestDuration)
+}
+
+// renderChaosSetup shows the length of the session, the ranges the
+// intervals are drawn from and the seed. The intervals themselves stay
+// hidden.
+func (m Model) renderChaosSetup() string {
+	t := m.timer
+	var s string
+	s += m.settingStyle(SettingDuration).Render(fmt.Sprintf("Duration: %d min", int(t.Duration.Minutes()))) + "\n"
+	s += m.settingStyle(SettingWork).Render(fmt.Sprintf("Shortest work: %ds", int(t.WorkDuration.Seconds()))) + "\n"
+	s += m.settingStyle(SettingWorkMax).Render(fmt.Sprintf("Longest work: %ds", int(t.WorkMax.Seconds()))) + "\n"
+	s += m.settingStyle(SettingRest).Render(fmt.Sprintf("Shortest rest: %ds", int(t.RestDuration.Seconds()))) + "\n"
+	s += m.settingStyle(SettingRestMax).Render(fmt.Sprintf("Longest rest: %ds", int(t.RestMax.Seconds()))) + "\n"
+	s += m.settingStyle(SettingSeed).Render(fmt.Sprintf("Seed: %d", t.Seed)) + "\n"
+	s += RoundStyle.Render(plural(t.TotalRounds, "round")+", same seed same session") + "\n"
+	return s
+}
+
+// renderChaosProgress shows the round and the time left in the session,
+// and the next interval when the coach asks for it
+func (m Model) renderChaosProgress() string {
+	t := m.timer
+	if m.state == StateFinished {
+		return RoundStyle.Render(fmt.Sprintf("%s · seed %d", plural(t.TotalRounds, "round"), t.Seed)) + "\n"
+	}
+
+	s := RoundStyle.Render(fmt.Sprintf("Round %d · %s left", t.Round, formatCountdown(t.SessionRemaining()))) + "\n"
+	if !m.peekNext {
+		return s
+	}
+	phase, length, ok := t.NextInterval()
+	next := "Next: finish"
+	if ok {
+		name := "work"
+		if phase == timer.PhaseRest {
+			name = "rest"
+		}
+		next = fmt.Sprintf("Next: %s %ds", name, int(length.Seconds()))
+	}
+	return s + lipgloss.NewStyle().Foreground(ColorAccent).Render(next) + "\n"
+}
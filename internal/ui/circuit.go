@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/timer"
+)
+
+// Station counts offered in setup
+const (
+	minStations = 2
+	maxStations = 12
+)
+
+// setStationCount adds or removes stations, keeping the number of laps.
+// New stations get default names.
+func (m *Model) setStationCount(n int) {
+	n = max(minStations, min(n, maxStations))
+	laps := m.timer.Laps()
+	// Copy first, so growing the list can't overwrite the names it was cut from
+	stations := slices.Clone(m.timer.Stations[:min(n, len(m.timer.Stations))])
+	stations = append(stations, timer.DefaultStations(n)[len(stations):]...)
+	m.timer.Stations = stations
+	m.timer.SetLaps(laps)
+}
+
+// circuitProgress describes where the circuit is, e.g. "Lap 1 of 2 ·
+// Station 3 of 6"
+func (m Model) circuitProgress() string {
+	t := m.timer
+	stint := (min(t.Round, t.TotalRounds)-1)%len(t.Stations) + 1
+	return fmt.Sprintf("Lap %d of %d · Station %d of %d", t.Lap(), t.Laps(), stint, len(t.Stations))
+}
+
+// renderStations shows each group's current and next station
+func (m Model) renderStations() string {
+	t := m.timer
+	round := min(t.Round, t.TotalRounds)
+	last := round >= t.TotalRounds
+	moving := t.Phase == timer.PhaseRest && m.state != StateFinished
+
+	header := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	current := SettingStyle
+	next := lipgloss.NewStyle().Foreground(ColorDim)
+	if moving {
+		// Everyone is heading to the next station
+		current = lipgloss.NewStyle().Foreground(ColorDim)
+		next = lipgloss.NewStyle().Foreground(ColorWork).Bold(true)
+	}
+
+	var b strings.Builder
+	b.WriteString(header.Render(fmt.Sprintf("%-6s %-16s %-16s", "GROUP", "NOW", "NEXT")) + "\n")
+	for g := 1; g <= t.Groups(); g++ {
+		upcoming := "—"
+		if !last {
+			upcoming = t.StationOf(g, round+1)
+		}
+		b.WriteString(SettingStyle.Render(fmt.Sprintf("%-6d ", g)))
+		b.WriteString(current.Render(fmt.Sprintf("%-16s ", truncate(t.StationOf(g, round), 16))))
+		b.WriteString(next.Render(fmt.Sprintf("%-16s", truncate(upcoming, 16))))
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderCircuitSetup lists the circuit settings
+func (m Model) renderCircuitSetup() string {
+	style := m.settingStyle
+	t := m.timer
+	var s string
+	s += style(SettingWork).Render(fmt.Sprintf("Work: %ds", int(t.WorkDuration.Seconds()))) + "\n"
+	s += style(SettingRest).Render(fmt.Sprintf("Move: %ds", int(t.RestDuration.Seconds()))) + "\n"
+	s += style(SettingStations).Render(fmt.Sprintf("Stations: %d", len(t.Stations))) + "\n"
+	s += style(SettingRounds).Render(fmt.Sprintf("Laps: %d", t.Laps())) + "\n"
+	s += RoundStyle.Render(strings.Join(t.Stations, " → ")) + "\n"
+	return s
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/history"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+)
+
+// Most reps a Death by can start with or add each minute in setup
+const (
+	maxStartReps = 50
+	maxRepStep   = 10
+)
+
+// scoreDeathBy scores a finished Death by: straight away if the athlete
+// lasted every minute, otherwise once the reps made in the failed minute
+// are entered
+func (m *Model) scoreDeathBy() {
+	if m.timer.Done {
+		m.scoring = true
+		m.scoreInput = ""
+		return
+	}
+
+	sc := score.Score{Kind: score.RoundsReps, Rounds: m.timer.CompletedMinutes()}
+	if err := history.SetScore(m.historyPath, m.result.start, sc); err != nil {
+		m.notice = "Could not save score: " + err.Error()
+		return
+	}
+	m.recordScore(sc)
+}
+
+// partialReps reports whether the score being entered is the reps made in
+// the minute a Death by athlete failed
+func (m Model) partialReps() bool {
+	return m.timer.Mode == timer.ModeDeathBy && m.timer.Done && m.scoreLane == 0
+}
+
+// deathByScore turns the reps made in the failed minute into the score:
+// completed minutes plus those reps
+func (m Model) deathByScore(partial score.Score) (score.Score, error) {
+	target := m.timer.RepTarget(m.timer.Round)
+	if partial.Reps >= target {
+		return score.Score{}, fmt.Errorf("minute %d only needed %d reps", m.timer.Round, target)
+	}
+	return score.Score{Kind: score.RoundsReps, Rounds: m.timer.CompletedMinutes(), Reps: partial.Reps}, nil
+}
+
+// renderRepTarget shows the reps due this minute, large, and the next
+// minute's
+func (m Model) renderRepTarget() string {
+	t := m.timer
+	if m.state == StateFinished {
+		return SettingStyle.Render(fmt.Sprintf("Completed minutes: %d", t.CompletedMinutes())) + "\n"
+	}
+
+	big := lipgloss.NewStyle().Bold(true).Foreground(ColorNeutral).MarginTop(1)
+	s := big.Render(strings.ToUpper(plural(t.RepTarget(t.Round), "rep"))) + "\n"
+	line := fmt.Sprintf("Minute %d  ·  next minute %s", t.Round, plural(t.RepTarget(t.Round+1), "rep"))
+	if m.started {
+		line += "  [Enter] Failed"
+	}
+	s += RoundStyle.Render(line) + "\n"
+	return s
+}
+
+// renderDeathBySetup lists the Death by settings
+func (m Model) renderDeathBySetup() string {
+	t := m.timer
+	var s string
+	s += m.settingStyle(SettingReps).Render("Start: "+plural(t.StartReps, "rep")) + "\n"
+	s += m.settingStyle(SettingStep).Render("Add: "+plural(t.RepStep, "rep")+" a minute") + "\n"
+	s += m.settingStyle(SettingRounds).Render(fmt.Sprintf("Max minutes: %d", t.TotalRounds)) + "\n"
+	s += RoundStyle.Render(fmt.Sprintf("%d, %d, %d, ... reps", t.RepTarget(1), t.RepTarget(2), t.RepTarget(3))) + "\n"
+	return s
+}
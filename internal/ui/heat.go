@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/export"
+	"gymtimer/internal/heat"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+)
+
+// WithHeat returns a model that times a heat of athletes, one per lane.
+// Only For Time and AMRAP workouts run heats.
+func (m Model) WithHeat(h *heat.Heat) Model {
+	m.heat = h
+	m.heatLanes = len(h.Lanes)
+	return m
+}
+
+// heatModes reports whether the current mode can run a heat
+func (m Model) heatModes() bool {
+	return m.timer.Mode == timer.ModeForTime || m.timer.Mode == timer.ModeAMRAP
+}
+
+// setupHeat creates or drops the heat once its setup is confirmed, keeping
+// the athletes' names
+func (m *Model) setupHeat() {
+	if !m.heatModes() || m.heatLanes == 0 {
+		m.heat = nil
+		return
+	}
+	if m.heat != nil && len(m.heat.Lanes) == m.heatLanes {
+		m.heat.Reset()
+		return
+	}
+
+	var athletes []string
+	if m.heat != nil {
+		for _, l := range m.heat.Lanes {
+			athletes = append(athletes, l.Athlete)
+		}
+	}
+	m.heat, _ = heat.New(m.heatLanes, athletes[:min(len(athletes), m.heatLanes)])
+}
+
+// laneKey returns the lane a number key stands for: 1-9, and 0 for lane 10
+func laneKey(msg tea.KeyMsg) (int, bool) {
+	s := msg.String()
+	if len(s) != 1 || s[0] < '0' || s[0] > '9' {
+		return 0, false
+	}
+	if s == "0" {
+		return 10, true
+	}
+	return int(s[0] - '0'), true
+}
+
+// handleHeatKey handles the lane keys once a heat has begun, reporting
+// whether the key was used
+func (m *Model) handleHeatKey(msg tea.KeyMsg) bool {
+	if !m.started {
+		return false
+	}
+
+	if n, ok := laneKey(msg); ok {
+		switch {
+		case n > len(m.heat.Lanes):
+		case m.state == StateFinished:
+			m.startLaneScore(n)
+		case m.timer.Mode == timer.ModeForTime && m.state == StateRunning:
+			m.captureLane(n)
+		}
+		// Number keys never switch modes in the middle of a heat
+		return true
+	}
+
+	switch {
+	case m.keys.Undo.Matches(msg) && m.timer.Mode == timer.ModeForTime:
+		if n, ok := m.heat.Undo(); ok {
+			m.notice = fmt.Sprintf("Cleared the finish of %s", m.heat.Lanes[n-1].Name())
+		}
+		return true
+	case m.keys.Results.Matches(msg):
+		m.heatResults = true
+		return true
+	}
+	return false
+}
+
+// captureLane records a lane's finish at the current time, ending the heat
+// once every lane is done
+func (m *Model) captureLane(n int) {
+	if !m.heat.Finish(n, m.timer.Elapsed) {
+		return
+	}
+	m.audio.PlayIntervalChange(true)
+	if m.heat.AllFinished() {
+		m.finishNow()
+	}
+}
+
+// laneScoreKind is how lanes without a finish time are scored: reps at the
+// For Time cap, otherwise the workout's own scoring
+func (m Model) laneScoreKind() score.Kind {
+	switch {
+	case m.timer.Mode == timer.ModeForTime:
+		return score.Reps
+	case m.benchmark != nil:
+		return m.benchmark.Scoring
+	default:
+		return score.RoundsReps
+	}
+}
+
+// startLaneScore opens score entry for a lane that did not finish
+func (m *Model) startLaneScore(n int) {
+	l, _ := m.heat.Lane(n)
+	if l.Finished() {
+		m.notice = fmt.Sprintf("%s finished in %s", l.Name(), l.Score.String())
+		return
+	}
+	m.scoreLane = n
+	m.scoring = true
+	m.scoreInput = ""
+}
+
+func (m *Model) handleResultsKey(msg tea.KeyMsg) {
+	switch {
+	case m.keys.Export.Matches(msg):
+		m.exportHeat()
+	case m.keys.AddToBoard.Matches(msg) && m.boardPath != "":
+		m.addHeatToBoard()
+	case m.keys.Results.Matches(msg), m.keys.Discard.Matches(msg):
+		m.heatResults = false
+	}
+}
+
+// exportHeat writes the ranked results to the export directory
+func (m *Model) exportHeat() {
+	path, err := export.SaveHeat(m.exportDir, m.exportFormat, m.heat)
+	if err != nil {
+		m.notice = "Export failed: " + err.Error()
+		return
+	}
+	m.notice = "Exported to " + path
+}
+
+// laneStatus describes how a lane is doing
+func (m Model) laneStatus(l heat.Lane) string {
+	switch {
+	case l.Score != nil:
+		return l.Score.String()
+	case !m.started:
+		return "ready"
+	case m.state == StateFinished && m.timer.Mode == timer.ModeForTime:
+		return "capped"
+	case m.state == StateFinished:
+		return "no score"
+	default:
+		return "racing"
+	}
+}
+
+// renderLanes draws the lane table shown beside the clock
+func (m Model) renderLanes() string {
+	header := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	finished := lipgloss.NewStyle().Foreground(ColorWork)
+
+	var b strings.Builder
+	b.WriteString(header.Render(fmt.Sprintf("%-4s %-14s %-10s %-4s", "LANE", "ATHLETE", "STATUS", "")) + "\n")
+	for _, l := range m.heat.Lanes {
+		rank := ""
+		if r := m.heat.Rank(l.Number); r > 0 {
+			rank = heat.Ordinal(r)
+		}
+		line := fmt.Sprintf("%-4d %-14s %-10s %-4s", l.Number, truncate(l.Name(), 14), m.laneStatus(l), rank)
+		if l.Finished() {
+			b.WriteString(finished.Render(line))
+		} else {
+			b.WriteString(SettingStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	keys := fmt.Sprintf("[1-%d]", len(m.heat.Lanes))
+	if len(m.heat.Lanes) == heat.MaxLanes {
+		keys = "[1-9,0]"
+	}
+	var help string
+	switch {
+	case m.state == StateFinished:
+		help = keys + " Score lane  [V] Results"
+	case m.timer.Mode == timer.ModeForTime && m.started:
+		help = keys + " Lane done  [U] Undo"
+	}
+	if help != "" {
+		b.WriteString(RoundStyle.Render(help))
+	}
+	return b.String()
+}
+
+// renderHeatResults shows the lanes in rank order
+func (m Model) renderHeatResults() string {
+	var s string
+	s += TitleStyle.Render("HEAT RESULTS") + "\n"
+	s += SettingStyle.Render(m.timer.Summary()+"  "+m.heat.Start.Format("2006-01-02 15:04")) + "\n\n"
+
+	for _, r := range m.heat.Results() {
+		rank := "—"
+		if r.Rank > 0 {
+			rank = heat.Ordinal(r.Rank)
+		}
+		result := m.laneStatus(r.Lane)
+		line := fmt.Sprintf("%-5s Lane %-3d %-16s %-10s", rank, r.Lane.Number, truncate(r.Lane.Name(), 16), result)
+		if r.Rank == 1 {
+			s += SettingSelectedStyle.Render(line) + "\n"
+		} else {
+			s += SettingStyle.Render(line) + "\n"
+		}
+	}
+
+	if m.notice != "" {
+		s += RoundStyle.Render(m.notice) + "\n"
+	}
+	help := "[E] Export results  [V] Back"
+	if m.boardPath != "" {
+		help = "[E] Export results  " + m.keys.AddToBoard.Help + "  [V] Back"
+	}
+	s += HelpStyle.Render(help)
+	return s
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// heatStart notes when the heat began
+func (m *Model) heatStart() {
+	if m.heat != nil {
+		m.heat.Reset()
+		m.heat.Start = time.Now()
+	}
+}
@@ -19,6 +19,10 @@ type KeyMap struct {
 	ModeAMRAP       Key
 	ModeCustom      Key
 	ModeStopwatch   Key
+	ModeForTime     Key
+	ModeCircuit     Key
+	ModeDeathBy     Key
+	ModeChaos       Key
 	StopwatchToggle Key
 	StopwatchReset  Key
 	Up              Key
@@ -27,6 +31,17 @@ type KeyMap struct {
 	Right           Key
 	Enter           Key
 	ToggleSound     Key
+	ResumePaused    Key
+	Discard         Key
+	Export          Key
+	Stats           Key
+	Benchmarks      Key
+	Undo            Key
+	Results         Key
+	Leaderboard     Key
+	AddToBoard      Key
+	Movements       Key
+	PeekNext        Key
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -68,6 +83,22 @@ func DefaultKeyMap() KeyMap {
 			Keys: []string{"6"},
 			Help: "[6] Stopwatch",
 		},
+		ModeForTime: Key{
+			Keys: []string{"7"},
+			Help: "[7] For Time",
+		},
+		ModeCircuit: Key{
+			Keys: []string{"8"},
+			Help: "[8] Circuit",
+		},
+		ModeDeathBy: Key{
+			Keys: []string{"9"},
+			Help: "[9] Death By",
+		},
+		ModeChaos: Key{
+			Keys: []string{"0"},
+			Help: "[0] Chaos",
+		},
 		StopwatchToggle: Key{
 			Keys: []string{"w"},
 			Help: "[W] Stopwatch Start/Stop",
@@ -100,6 +131,50 @@ func DefaultKeyMap() KeyMap {
 			Keys: []string{"s"},
 			Help: "[S] Sound",
 		},
+		ResumePaused: Key{
+			Keys: []string{"p"},
+			Help: "[P] Resume paused",
+		},
+		Discard: Key{
+			Keys: []string{"n", "esc"},
+			Help: "[N] Discard",
+		},
+		Export: Key{
+			Keys: []string{"e"},
+			Help: "[E] Export session",
+		},
+		Stats: Key{
+			Keys: []string{"t"},
+			Help: "[T] Stats",
+		},
+		Benchmarks: Key{
+			Keys: []string{"b"},
+			Help: "[B] Benchmarks",
+		},
+		Undo: Key{
+			Keys: []string{"u"},
+			Help: "[U] Undo",
+		},
+		Results: Key{
+			Keys: []string{"v"},
+			Help: "[V] Results",
+		},
+		Leaderboard: Key{
+			Keys: []string{"L"},
+			Help: "[L] Leaderboard",
+		},
+		AddToBoard: Key{
+			Keys: []string{"a"},
+			Help: "[A] Add to leaderboard",
+		},
+		Movements: Key{
+			Keys: []string{"m"},
+			Help: "[M] Movements",
+		},
+		PeekNext: Key{
+			Keys: []string{"c"},
+			Help: "[C] Next interval",
+		},
 	}
 }
 
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gymtimer/internal/timer"
+)
+
+// Progressions offered in setup, in order
+var progressions = []timer.Progression{timer.Fixed, timer.Ladder, timer.Pyramid}
+
+// Largest step a progression can take each round in setup
+const maxStep = time.Minute
+
+// adjustProgression switches between fixed intervals, ladders and
+// pyramids. A new progression starts adding 10s of work a round.
+func (m *Model) adjustProgression(delta int) {
+	i := 0
+	for j, p := range progressions {
+		if p == m.timer.Progression {
+			i = j
+		}
+	}
+	i = (i + delta + len(progressions)) % len(progressions)
+	m.timer.Progression = progressions[i]
+	if m.timer.Progression != timer.Fixed && m.timer.WorkStep == 0 && m.timer.RestStep == 0 {
+		m.timer.WorkStep = 10 * time.Second
+	}
+}
+
+// adjustStep changes how much work each round adds
+func (m *Model) adjustStep(delta int) {
+	step := m.timer.WorkStep + time.Duration(delta*5)*time.Second
+	m.timer.WorkStep = max(-maxStep, min(step, maxStep))
+}
+
+// intervalField returns the Tabata or custom setting delta places from
+// the current one. The rest between sets is only offered with sets, and
+// the step only with a progression.
+func (m Model) intervalField(delta int) SettingField {
+	fields := []SettingField{SettingWork, SettingRest, SettingRounds, SettingSets}
+	if m.timer.HasSets() {
+		fields = append(fields, SettingSetRest)
+	}
+	if m.timer.Mode == timer.ModeCustom {
+		fields = append(fields, SettingProgression)
+		if m.timer.Progression != timer.Fixed {
+			fields = append(fields, SettingStep)
+		}
+	}
+	i := 0
+	for j, f := range fields {
+		if f == m.settingField {
+			i = j
+		}
+	}
+	return fields[(i+delta+len(fields))%len(fields)]
+}
+
+// renderProgressionSetup shows the progression settings and, when the
+// intervals change, every round's work and rest with the total time
+func (m Model) renderProgressionSetup() string {
+	progression := "fixed"
+	if m.timer.Progression != timer.Fixed {
+		progression = string(m.timer.Progression)
+	}
+	var s string
+	s += m.settingStyle(SettingProgression).Render("Progression: "+progression) + "\n"
+	if m.timer.Progression == timer.Fixed {
+		return s
+	}
+
+	step := fmt.Sprintf("Step: %s work", signedSeconds(m.timer.WorkStep))
+	if m.timer.RestStep != 0 {
+		step += fmt.Sprintf(", %s rest", signedSeconds(m.timer.RestStep))
+	}
+	s += m.settingStyle(SettingStep).Render(step+" a round") + "\n"
+	if !m.timer.Progresses() {
+		return s
+	}
+
+	// The schedule, a few rounds to a line
+	const perLine = 6
+	var lines []string
+	var line []string
+	for round := 1; round <= m.timer.TotalRounds; round++ {
+		line = append(line, fmt.Sprintf("%d/%d", int(m.timer.WorkFor(round).Seconds()), int(m.timer.RestFor(round).Seconds())))
+		if len(line) == perLine || round == m.timer.TotalRounds {
+			lines = append(lines, strings.Join(line, "  "))
+			line = nil
+		}
+	}
+	total := timer.CustomTimer{Timer: m.timer}
+	s += RoundStyle.Render("Schedule (work/rest s):\n"+strings.Join(lines, "\n")) + "\n"
+	s += SettingStyle.Render("Total: "+formatCountdown(total.TotalWorkoutDuration())) + "\n"
+	return s
+}
+
+// signedSeconds writes a step in seconds with its sign, e.g. "+10s"
+func signedSeconds(d time.Duration) string {
+	return fmt.Sprintf("%+ds", int(d.Seconds()))
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/heat"
+	"gymtimer/internal/leaderboard"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+)
+
+// WithLeaderboard lets the timer show the competition kept in path, and
+// add heat results to it. With show set the leaderboard opens right away.
+func (m Model) WithLeaderboard(path string, show bool) Model {
+	m.boardPath = path
+	if show {
+		m.openBoard()
+	}
+	return m
+}
+
+// openBoard loads the competition and shows the leaderboard
+func (m *Model) openBoard() {
+	c, err := leaderboard.Load(m.boardPath)
+	if err != nil {
+		m.notice = "Could not read leaderboard: " + err.Error()
+		return
+	}
+	m.board = c
+	m.boardPage = 0
+}
+
+// refreshBoard reloads the competition so results recorded elsewhere show
+// up on an open leaderboard
+func (m *Model) refreshBoard() {
+	if c, err := leaderboard.Load(m.boardPath); err == nil {
+		m.board = c
+		m.boardPage = min(m.boardPage, len(c.Workouts))
+	}
+}
+
+// handleBoardKey pages through the overall standings and each workout
+func (m *Model) handleBoardKey(msg tea.KeyMsg) {
+	pages := len(m.board.Workouts) + 1
+	switch {
+	case m.keys.Right.Matches(msg):
+		m.boardPage = (m.boardPage + 1) % pages
+	case m.keys.Left.Matches(msg):
+		m.boardPage = (m.boardPage + pages - 1) % pages
+	case m.keys.Leaderboard.Matches(msg), m.keys.Discard.Matches(msg):
+		m.board = nil
+	}
+}
+
+// heatWorkout names the workout a heat's results are filed under
+func (m Model) heatWorkout() (string, score.Kind) {
+	name := m.timer.Name
+	if m.benchmark != nil {
+		name = m.benchmark.Name()
+	}
+	if name == "" {
+		name = m.timer.Summary()
+	}
+	if m.timer.Mode == timer.ModeForTime {
+		return name, score.Time
+	}
+	return name, m.laneScoreKind()
+}
+
+// addHeatToBoard records every named lane's result in the competition,
+// adding the workout if it is new
+func (m *Model) addHeatToBoard() {
+	c, err := leaderboard.Load(m.boardPath)
+	if err != nil {
+		m.notice = "Could not read leaderboard: " + err.Error()
+		return
+	}
+
+	name, kind := m.heatWorkout()
+	if w, ok := c.Workout(name); ok {
+		name = w.Name
+	} else if err := c.AddWorkout(name, kind); err != nil {
+		m.notice = err.Error()
+		return
+	}
+
+	added, unnamed := 0, 0
+	for _, l := range m.heat.Lanes {
+		switch {
+		case l.Score == nil:
+		case l.Athlete == "":
+			unnamed++
+		default:
+			if err := c.Record(name, l.Athlete, *l.Score, time.Now()); err != nil {
+				m.notice = err.Error()
+				return
+			}
+			added++
+		}
+	}
+	if err := c.Save(m.boardPath); err != nil {
+		m.notice = "Could not save leaderboard: " + err.Error()
+		return
+	}
+
+	m.notice = fmt.Sprintf("Added %s to %s", plural(added, "result"), name)
+	if unnamed > 0 {
+		m.notice += fmt.Sprintf(" (skipped %s without an athlete)", plural(unnamed, "lane"))
+	}
+}
+
+func (m Model) renderBoard() string {
+	c := m.board
+	title := "LEADERBOARD"
+	if c.Name != "" {
+		title = strings.ToUpper(c.Name)
+	}
+
+	var s string
+	s += TitleStyle.Render(title) + "\n"
+	if len(c.Workouts) == 0 {
+		s += SettingStyle.Render("No workouts yet") + "\n"
+		s += HelpStyle.Render("[L] Back")
+		return s
+	}
+
+	if m.boardPage == 0 {
+		s += m.renderStandings()
+	} else {
+		s += m.renderRanking(c.Workouts[m.boardPage-1])
+	}
+
+	if m.notice != "" {
+		s += RoundStyle.Render(m.notice) + "\n"
+	}
+	s += HelpStyle.Render("[Left/Right] Overall / workouts  [L] Back")
+	return s
+}
+
+// renderStandings shows the overall standings with each workout's place
+func (m Model) renderStandings() string {
+	c := m.board
+	scoring := "Most points wins"
+	if c.LowestWins() {
+		scoring = "Points are places; lowest total wins"
+	}
+
+	var b strings.Builder
+	b.WriteString(RoundStyle.Render("Overall  ·  "+scoring) + "\n\n")
+
+	header := fmt.Sprintf("%-5s %-18s %6s", "RANK", "ATHLETE", "POINTS")
+	for _, w := range c.Workouts {
+		header += fmt.Sprintf("  %-8s", truncate(strings.ToUpper(w.Name), 8))
+	}
+	b.WriteString(SettingSelectedStyle.Render(header) + "\n")
+
+	for _, st := range c.Standings() {
+		line := fmt.Sprintf("%-5s %-18s %6d", heat.Ordinal(st.Rank), truncate(st.Athlete, 18), st.Total)
+		for _, p := range st.Placings {
+			place := "-"
+			if p.Score != nil {
+				place = heat.Ordinal(p.Place)
+			}
+			line += fmt.Sprintf("  %-8s", place)
+		}
+		if st.Rank == 1 {
+			b.WriteString(lipgloss.NewStyle().Foreground(ColorWork).Bold(true).Render(line) + "\n")
+		} else {
+			b.WriteString(SettingStyle.Render(line) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// renderRanking shows one workout's results in place order
+func (m Model) renderRanking(w leaderboard.Workout) string {
+	var b strings.Builder
+	b.WriteString(RoundStyle.Render(fmt.Sprintf("%s  ·  scored by %s", w.Name, w.Scoring)) + "\n\n")
+	b.WriteString(SettingSelectedStyle.Render(fmt.Sprintf("%-5s %-18s %-10s %6s", "PLACE", "ATHLETE", "SCORE", "POINTS")) + "\n")
+
+	for _, p := range m.board.Ranking(w.Name) {
+		result := "-"
+		if p.Score != nil {
+			result = p.Score.String()
+		}
+		line := fmt.Sprintf("%-5s %-18s %-10s %6d", heat.Ordinal(p.Place), truncate(p.Athlete, 18), result, p.Points)
+		b.WriteString(SettingStyle.Render(line) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/timer"
+)
+
+// Longest movement label or note that can be typed
+const maxMovementText = 40
+
+// movementModes reports whether the current mode labels its rounds with
+// movements
+func (m Model) movementModes() bool {
+	switch m.timer.Mode {
+	case timer.ModeEMOM, timer.ModeTabata, timer.ModeCustom:
+		return true
+	default:
+		return false
+	}
+}
+
+// openMovements starts editing the movements from the first round
+func (m *Model) openMovements() {
+	m.editingMoves = true
+	m.moveRound = 1
+	m.moveNote = false
+}
+
+// handleMovementKey edits the movement of the selected round. Letters are
+// text here, so no other key bindings apply.
+func (m *Model) handleMovementKey(msg tea.KeyMsg) {
+	mv, _ := m.timer.Movement(m.moveRound)
+	text := &mv.Label
+	if m.moveNote {
+		text = &mv.Note
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyEsc:
+		m.editingMoves = false
+		return
+	case tea.KeyUp:
+		m.moveRound = max(1, m.moveRound-1)
+		return
+	case tea.KeyDown:
+		m.moveRound = min(m.moveRound+1, m.movementSlots())
+		return
+	case tea.KeyTab:
+		m.moveNote = !m.moveNote
+		return
+	case tea.KeyBackspace:
+		if r := []rune(*text); len(r) > 0 {
+			*text = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		if len([]rune(*text)) < maxMovementText {
+			*text += " "
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if len([]rune(*text)) < maxMovementText {
+				*text += string(r)
+			}
+		}
+	default:
+		return
+	}
+	m.timer.SetMovement(m.moveRound, mv)
+}
+
+// renderMovementEditor lists every round's movement with the selected one
+// open for typing
+func (m Model) renderMovementEditor() string {
+	var s string
+	s += TitleStyle.Render("MOVEMENTS: "+m.timer.Summary()) + "\n"
+
+	// Show a window of rounds around the selected one
+	slots := m.movementSlots()
+	first := max(1, min(m.moveRound-5, slots-9))
+	last := min(first+9, slots)
+	for round := first; round <= last; round++ {
+		mv, _ := m.timer.Movement(round)
+		label, note := mv.Label, mv.Note
+		if round == m.moveRound {
+			if m.moveNote {
+				note += "_"
+			} else {
+				label += "_"
+			}
+		}
+		line := fmt.Sprintf("%-6s %-3d %-*s %-*s", m.roundWord(), round, maxMovementText+1, label, maxMovementText+1, note)
+		if round == m.moveRound {
+			s += SettingSelectedStyle.Render(line) + "\n"
+		} else {
+			s += SettingStyle.Render(line) + "\n"
+		}
+	}
+
+	field := "label"
+	if m.moveNote {
+		field = "note"
+	}
+	s += RoundStyle.Render("Editing the "+field) + "\n"
+	s += HelpStyle.Render("[Up/Down] Round  [Tab] Label/Note  [Enter] Done")
+	return s
+}
+
+// movementSlots is how many movements can be set: one per round, or one
+// per station of an EMOM rotation
+func (m Model) movementSlots() int {
+	if m.timer.Rotates() {
+		return min(m.timer.Rotation, m.timer.TotalRounds)
+	}
+	return m.timer.TotalRounds
+}
+
+// roundWord is what a round is called in the current mode
+func (m Model) roundWord() string {
+	switch {
+	case m.timer.Rotates():
+		return "Station"
+	case m.timer.Mode == timer.ModeEMOM:
+		return "Minute"
+	default:
+		return "Round"
+	}
+}
+
+// renderMovementSetup summarises the movements on the setup screen
+func (m Model) renderMovementSetup() string {
+	labelled := 0
+	for round := 1; round <= m.movementSlots(); round++ {
+		if _, ok := m.timer.Movement(round); ok {
+			labelled++
+		}
+	}
+	if labelled == 0 {
+		return SettingStyle.Render("Movements: none") + "\n"
+	}
+	unit := "rounds"
+	if m.timer.Rotates() {
+		unit = "stations"
+	}
+	return SettingStyle.Render(fmt.Sprintf("Movements: %d of %d %s", labelled, m.movementSlots(), unit)) + "\n"
+}
+
+// Most movements an EMOM can rotate through
+const maxRotation = 8
+
+// setRotation sets how many movements EMOM rounds cycle through. A
+// rotation of one is no rotation, so it is skipped.
+func (m *Model) setRotation(n int) {
+	if n == 1 {
+		if m.timer.Rotation == 0 {
+			n = 2
+		} else {
+			n = 0
+		}
+	}
+	m.timer.Rotation = max(0, min(n, maxRotation))
+}
+
+// renderRotationSetup shows the EMOM rotation setting
+func (m Model) renderRotationSetup() string {
+	style := SettingStyle
+	if m.settingField == SettingStations {
+		style = SettingSelectedStyle
+	}
+	rotation := "off"
+	if m.timer.Rotates() {
+		rotation = fmt.Sprintf("%d movements", m.timer.Rotation)
+	}
+	return style.Render("Rotation: "+rotation) + "\n"
+}
+
+// rotationProgress describes where a rotating EMOM is, e.g. "Minute 7 of
+// 20 — Station 3/4"
+func (m Model) rotationProgress() string {
+	t := m.timer
+	round := min(t.Round, t.TotalRounds)
+	return fmt.Sprintf("Minute %d of %d — Station %d/%d", round, t.TotalRounds, t.Station(round), t.Rotation)
+}
+
+// renderMovement shows the current movement large under the time, with
+// the next one previewed
+func (m Model) renderMovement() string {
+	t := m.timer
+	if m.state == StateFinished {
+		return ""
+	}
+
+	big := lipgloss.NewStyle().Bold(true).Foreground(ColorNeutral).MarginTop(1)
+	note := lipgloss.NewStyle().Foreground(ColorAccent)
+
+	var s string
+	current, ok := t.CurrentMovement()
+	next, hasNext := t.NextMovement()
+	switch {
+	case t.Phase == timer.PhaseRest && hasNext:
+		// Resting: get ready for what comes next
+		s += big.Foreground(ColorRest).Render("NEXT: "+strings.ToUpper(next.Label)) + "\n"
+		if next.Note != "" {
+			s += note.Render(next.Note) + "\n"
+		}
+		return s
+	case t.Phase == timer.PhaseRest:
+		return ""
+	case ok:
+		s += big.Render(strings.ToUpper(current.Label)) + "\n"
+		if current.Note != "" {
+			s += note.Render(current.Note) + "\n"
+		}
+	}
+
+	switch {
+	case hasNext:
+		preview := "Next: " + next.Label
+		if next.Note != "" {
+			preview += " (" + next.Note + ")"
+		}
+		s += RoundStyle.Render(preview) + "\n"
+	case t.Round >= t.TotalRounds:
+		s += RoundStyle.Render("Last round") + "\n"
+	}
+	return s
+}
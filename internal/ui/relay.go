@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/timer"
+)
+
+// How long the changeover banner stays up once the next athlete takes over
+const changeoverBanner = 3 * time.Second
+
+// renderRelay shows who is working now and who takes over next
+func (m Model) renderRelay() string {
+	t := m.timer
+	if m.state == StateFinished {
+		return ""
+	}
+
+	var s string
+	if m.started && t.Round > 1 && t.Phase == timer.PhaseWork && t.Elapsed < changeoverBanner {
+		s += lipgloss.NewStyle().Foreground(ColorAccent).Bold(true).
+			Render("CHANGEOVER → "+strings.ToUpper(t.CurrentAthlete())) + "\n"
+	}
+
+	now := "NOW: " + t.CurrentAthlete()
+	if t.Phase == timer.PhaseRest {
+		now = "REST: " + t.CurrentAthlete()
+	}
+	next := "LAST ROUND"
+	if t.NextAthlete() != "" {
+		next = "NEXT: " + t.NextAthlete()
+	}
+	s += lipgloss.NewStyle().Foreground(ColorWork).Bold(true).Render(now) +
+		SettingStyle.Render(" — "+next) + "\n"
+	return s
+}
+
+// renderWorkTotals lists how long each athlete worked
+func (m Model) renderWorkTotals() string {
+	totals := m.timer.WorkTotals()
+	parts := make([]string, len(totals))
+	for i, d := range totals {
+		parts[i] = fmt.Sprintf("%s %s", m.timer.Athletes[i], formatCountdown(d))
+	}
+	return SettingStyle.Render("Work: " + strings.Join(parts, " · "))
+}
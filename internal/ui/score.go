@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"gymtimer/internal/history"
+	"gymtimer/internal/score"
+	"gymtimer/internal/timer"
+)
+
+// scoreKind returns how the finished workout is scored, if athletes log a
+// score for it
+func (m Model) scoreKind() (score.Kind, bool) {
+	switch {
+	case m.heat != nil:
+		// Each lane is scored on its own
+		return "", false
+	case m.timer.Mode == timer.ModeForTime && !m.timer.Done:
+		// Capped before finishing: score the reps completed
+		return score.Reps, true
+	case m.benchmark != nil:
+		return m.benchmark.Scoring, true
+	case m.timer.Mode == timer.ModeAMRAP, m.timer.Mode == timer.ModeDeathBy:
+		return score.RoundsReps, true
+	case m.timer.Mode == timer.ModeForTime:
+		return score.Time, true
+	default:
+		return "", false
+	}
+}
+
+// handleScoreKey edits the score being entered on the finished screen
+func (m *Model) handleScoreKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.saveScore()
+	case tea.KeyEsc:
+		m.scoring = false
+		m.scoreLane = 0
+		m.scoreInput = ""
+	case tea.KeyBackspace:
+		if m.scoreInput != "" {
+			m.scoreInput = m.scoreInput[:len(m.scoreInput)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if strings.ContainsRune("0123456789+:.", r) && len(m.scoreInput) < 12 {
+				m.scoreInput += string(r)
+			}
+		}
+	}
+}
+
+// saveScore attaches the entered score to the most recently recorded
+// session
+func (m *Model) saveScore() {
+	sc, err := score.Parse(m.entryKind(), m.scoreInput)
+	if err != nil {
+		m.notice = err.Error()
+		return
+	}
+
+	if m.partialReps() {
+		if sc, err = m.deathByScore(sc); err != nil {
+			m.notice = err.Error()
+			return
+		}
+	}
+
+	if m.scoreLane > 0 {
+		m.heat.SetScore(m.scoreLane, sc)
+		m.scoring = false
+		m.scoreLane = 0
+		m.scoreInput = ""
+		return
+	}
+
+	if m.result == nil {
+		m.notice = "No recorded session to score"
+		return
+	}
+	if err := history.SetScore(m.historyPath, m.result.start, sc); err != nil {
+		m.notice = "Could not save score: " + err.Error()
+		return
+	}
+
+	m.scoring = false
+	m.scoreInput = ""
+	m.notice = "Score saved: " + sc.String()
+	m.recordScore(sc)
+}
+
+// entryKind is how the score being entered is read
+func (m Model) entryKind() score.Kind {
+	if m.scoreLane > 0 {
+		return m.laneScoreKind()
+	}
+	if m.partialReps() {
+		return score.Reps
+	}
+	kind, _ := m.scoreKind()
+	return kind
+}
+
+// renderScoreEntry shows the score being typed
+func (m Model) renderScoreEntry() string {
+	kind := m.entryKind()
+	label := "Score"
+	if m.scoreLane > 0 {
+		l, _ := m.heat.Lane(m.scoreLane)
+		label = l.Name() + " score"
+	}
+	hint := map[score.Kind]string{
+		score.Time:       "M:SS",
+		score.Reps:       "reps",
+		score.RoundsReps: "rounds+reps",
+		score.Load:       "weight",
+	}[kind]
+	prompt := label + " (" + hint + ")"
+	if m.partialReps() {
+		prompt = fmt.Sprintf("Reps made in minute %d (of %d)", m.timer.Round, m.timer.RepTarget(m.timer.Round))
+	}
+
+	help := "[Enter] Save  [Esc] Cancel"
+	if m.notice != "" {
+		help = m.notice
+	}
+	return SettingSelectedStyle.Render(prompt+": "+m.scoreInput+"_") + "\n" +
+		RoundStyle.Render(help)
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"fmt"
+)
+
+// Most sets offered in setup
+const maxSets = 10
+
+// setsProgress describes where a workout in sets is, e.g. "Set 2/4 ·
+// Round 5/8"
+func (m Model) setsProgress() string {
+	t := m.timer
+	round := min(t.Round, t.TotalRounds)
+	return fmt.Sprintf("Set %d/%d · Round %d/%d", t.SetOf(round), t.Sets, t.RoundInSet(round), t.RoundsPerSet())
+}
+
+// renderSetsSetup shows the number of sets and, with more than one, the
+// rest between them
+func (m Model) renderSetsSetup() string {
+	s := m.settingStyle(SettingSets).Render(fmt.Sprintf("Sets: %d", max(1, m.timer.Sets))) + "\n"
+	if m.timer.HasSets() {
+		s += m.settingStyle(SettingSetRest).Render(fmt.Sprintf("Set rest: %ds", int(m.timer.SetRest.Seconds()))) + "\n"
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gymtimer/internal/history"
+	"gymtimer/internal/stats"
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// openStats loads the history and shows the statistics screen
+func (m *Model) openStats() {
+	sessions, err := history.Load(m.historyPath)
+	if err != nil {
+		m.notice = "Could not read history: " + err.Error()
+		return
+	}
+	summary := stats.Compute(sessions, time.Now())
+	m.stats = &summary
+}
+
+func (m Model) renderStats() string {
+	st := m.stats
+	var s string
+
+	s += TitleStyle.Render("TRAINING STATS") + "\n\n"
+
+	if st.Sessions == 0 {
+		s += SettingStyle.Render("No finished sessions yet") + "\n"
+		s += HelpStyle.Render("[T] Back")
+		return s
+	}
+
+	s += SettingStyle.Render(fmt.Sprintf("Sessions: %d   Training time: %s", st.Sessions, formatTraining(st.Active))) + "\n"
+	s += SettingStyle.Render(fmt.Sprintf("Streak: %s (longest %s)", plural(st.CurrentStreak, "day"), plural(st.LongestStreak, "day"))) + "\n"
+
+	s += RoundStyle.Render(fmt.Sprintf("Weekly training time, last %d weeks", stats.Weeks)) + "\n"
+	s += Sparkline(st.Weekly) + "  " + SettingStyle.Render("this week "+formatTraining(st.Weekly[len(st.Weekly)-1])) + "\n"
+
+	s += RoundStyle.Render(fmt.Sprintf("Daily, last %d days", stats.Days)) + "\n"
+	s += Sparkline(st.Daily) + "  " + SettingStyle.Render("today "+formatTraining(st.Daily[len(st.Daily)-1])) + "\n"
+
+	s += RoundStyle.Render("Sessions per mode") + "\n"
+	modes := make([]string, 0, len(st.PerMode))
+	most := 0
+	for mode, n := range st.PerMode {
+		modes = append(modes, mode)
+		most = max(most, n)
+	}
+	sort.Slice(modes, func(i, j int) bool {
+		if st.PerMode[modes[i]] != st.PerMode[modes[j]] {
+			return st.PerMode[modes[i]] > st.PerMode[modes[j]]
+		}
+		return modes[i] < modes[j]
+	})
+	for _, mode := range modes {
+		n := st.PerMode[mode]
+		// Pad the bars to one width so the centered rows line up
+		width := max(1, n*20/most)
+		bar := strings.Repeat("█", width) + strings.Repeat(" ", 20-width)
+		s += SettingStyle.Render(fmt.Sprintf("%-10s %4d ", strings.ToUpper(mode), n)) +
+			lipgloss.NewStyle().Foreground(ColorAccent).Render(bar) + "\n"
+	}
+
+	if len(st.Records) > 0 {
+		s += RoundStyle.Render("Personal records") + "\n"
+		for _, r := range st.Records {
+			line := fmt.Sprintf("%-20s %10s  %s", r.Workout, r.Score.String(), r.Date.Local().Format("2006-01-02"))
+			s += SettingStyle.Render(line) + "\n"
+		}
+	}
+
+	s += HelpStyle.Render("[T] Back")
+	return s
+}
+
+// Sparkline draws one bar per value, scaled to the largest
+func Sparkline(values []time.Duration) string {
+	var top time.Duration
+	for _, v := range values {
+		top = max(top, v)
+	}
+
+	bar := lipgloss.NewStyle().Foreground(ColorAccent)
+	empty := lipgloss.NewStyle().Foreground(ColorDim)
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || top == 0 {
+			b.WriteString(empty.Render(string(sparkLevels[0])))
+			continue
+		}
+		level := int(int64(v) * int64(len(sparkLevels)-1) / int64(top))
+		b.WriteString(bar.Render(string(sparkLevels[level])))
+	}
+	return b.String()
+}
+
+// formatTraining formats a training time as "1h 20m" or "25m"
+func formatTraining(d time.Duration) string {
+	mins := int(d.Round(time.Minute).Minutes())
+	if mins >= 60 {
+		return fmt.Sprintf("%dh %02dm", mins/60, mins%60)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Colors
 var (
@@ -13,51 +17,129 @@ var (
 	ColorAccent   = lipgloss.Color("#00CCFF") // Cyan accent
 )
 
+// Theme is a named color palette
+type Theme struct {
+	Work     lipgloss.Color
+	Rest     lipgloss.Color
+	Paused   lipgloss.Color
+	Finished lipgloss.Color
+	Neutral  lipgloss.Color
+	Dim      lipgloss.Color
+	Accent   lipgloss.Color
+}
+
+// Themes available to ApplyTheme
+var Themes = map[string]Theme{
+	"default": {
+		Work:     ColorWork,
+		Rest:     ColorRest,
+		Paused:   ColorPaused,
+		Finished: ColorFinished,
+		Neutral:  ColorNeutral,
+		Dim:      ColorDim,
+		Accent:   ColorAccent,
+	},
+	// Brighter, fully saturated colors for TVs across the gym
+	"high-contrast": {
+		Work:     lipgloss.Color("#00FF00"),
+		Rest:     lipgloss.Color("#FF00FF"),
+		Paused:   lipgloss.Color("#FFFF00"),
+		Finished: lipgloss.Color("#FF0000"),
+		Neutral:  lipgloss.Color("#FFFFFF"),
+		Dim:      lipgloss.Color("#AAAAAA"),
+		Accent:   lipgloss.Color("#00FFFF"),
+	},
+	// For light terminal backgrounds
+	"light": {
+		Work:     lipgloss.Color("#007700"),
+		Rest:     lipgloss.Color("#CC4400"),
+		Paused:   lipgloss.Color("#886600"),
+		Finished: lipgloss.Color("#CC0000"),
+		Neutral:  lipgloss.Color("#000000"),
+		Dim:      lipgloss.Color("#777777"),
+		Accent:   lipgloss.Color("#0055AA"),
+	},
+}
+
+// ApplyTheme switches the colors and styles to the named theme
+func ApplyTheme(name string) error {
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	ColorWork = theme.Work
+	ColorRest = theme.Rest
+	ColorPaused = theme.Paused
+	ColorFinished = theme.Finished
+	ColorNeutral = theme.Neutral
+	ColorDim = theme.Dim
+	ColorAccent = theme.Accent
+	buildStyles()
+	return nil
+}
+
 // Styles
 var (
+	TitleStyle           lipgloss.Style
+	TimeStyle            lipgloss.Style
+	PhaseWorkStyle       lipgloss.Style
+	PhaseRestStyle       lipgloss.Style
+	RoundStyle           lipgloss.Style
+	HelpStyle            lipgloss.Style
+	SettingStyle         lipgloss.Style
+	SettingSelectedStyle lipgloss.Style
+
+	// Container style for centering
+	ContainerStyle = lipgloss.NewStyle()
+)
+
+func init() {
+	buildStyles()
+}
+
+// buildStyles creates the styles from the current colors
+func buildStyles() {
 	// Title style for mode name
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorAccent).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(ColorAccent).
+		MarginBottom(1)
 
 	// Large time display style
 	TimeStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorNeutral)
+		Bold(true).
+		Foreground(ColorNeutral)
 
 	// Phase indicator (WORK/REST)
 	PhaseWorkStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorWork).
-			MarginTop(1)
+		Bold(true).
+		Foreground(ColorWork).
+		MarginTop(1)
 
 	PhaseRestStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorRest).
-			MarginTop(1)
+		Bold(true).
+		Foreground(ColorRest).
+		MarginTop(1)
 
 	// Round counter
 	RoundStyle = lipgloss.NewStyle().
-			Foreground(ColorDim).
-			MarginTop(1)
+		Foreground(ColorDim).
+		MarginTop(1)
 
 	// Help bar at bottom
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorDim).
-			MarginTop(2)
+		Foreground(ColorDim).
+		MarginTop(2)
 
 	// Settings style
 	SettingStyle = lipgloss.NewStyle().
-			Foreground(ColorNeutral)
+		Foreground(ColorNeutral)
 
 	SettingSelectedStyle = lipgloss.NewStyle().
-				Foreground(ColorAccent).
-				Bold(true)
-
-	// Container style for centering
-	ContainerStyle = lipgloss.NewStyle()
-)
+		Foreground(ColorAccent).
+		Bold(true)
+}
 
 // Big digit font (7 segment style)
 var bigDigits = map[rune][]string{
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+)
+
+const (
+	queueSize      = 64
+	defaultTimeout = 5 * time.Second
+	defaultRetries = 3
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+	closeGrace     = 2 * time.Second
+
+	// SignatureHeader carries "sha256=<hex HMAC of the body>" when a secret is set
+	SignatureHeader = "X-Gymtimer-Signature"
+	// EventHeader carries the event kind
+	EventHeader = "X-Gymtimer-Event"
+)
+
+// Sender delivers events to webhook endpoints. Each endpoint has its own
+// bounded queue and worker so a slow endpoint never delays the others or
+// the caller.
+type Sender struct {
+	endpoints []*endpoint
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
+	// Guards the queues against sends once Close has closed them
+	mu     sync.RWMutex
+	closed bool
+
+	// OnError is called from a worker goroutine when delivery fails
+	OnError func(err error)
+}
+
+type endpoint struct {
+	cfg     config.Webhook
+	retries int
+	client  *http.Client
+	queue   chan delivery
+}
+
+type delivery struct {
+	kind events.Kind
+	body []byte
+}
+
+// New creates a sender for the given webhooks and starts its workers
+func New(hooks []config.Webhook) *Sender {
+	s := &Sender{stop: make(chan struct{})}
+	for _, cfg := range hooks {
+		timeout := time.Duration(cfg.Timeout)
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		retries := defaultRetries
+		if cfg.Retries != nil {
+			retries = max(0, *cfg.Retries)
+		}
+		ep := &endpoint{
+			cfg:     cfg,
+			retries: retries,
+			client:  &http.Client{Timeout: timeout},
+			queue:   make(chan delivery, queueSize),
+		}
+		s.endpoints = append(s.endpoints, ep)
+		s.wg.Add(1)
+		go s.work(ep)
+	}
+	return s
+}
+
+// Send queues the event for every matching endpoint. When an endpoint's
+// queue is full the event is dropped for that endpoint, and after Close
+// it is dropped for all of them.
+func (s *Sender) Send(e events.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		s.fail(err)
+		return
+	}
+
+	for _, ep := range s.endpoints {
+		if !events.Matches(ep.cfg.On, e.Kind) {
+			continue
+		}
+		select {
+		case ep.queue <- delivery{kind: e.Kind, body: body}:
+		default:
+			s.fail(fmt.Errorf("webhook %s: queue full, dropped %s event", ep.cfg.URL, e.Kind))
+		}
+	}
+}
+
+// Close stops the workers, giving queued events a short grace period to be
+// delivered first.
+func (s *Sender) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		for _, ep := range s.endpoints {
+			close(ep.queue)
+		}
+		s.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			s.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(closeGrace):
+		}
+		close(s.stop)
+	})
+}
+
+func (s *Sender) work(ep *endpoint) {
+	defer s.wg.Done()
+	for d := range ep.queue {
+		if err := s.deliver(ep, d); err != nil {
+			s.fail(fmt.Errorf("webhook %s: %w", ep.cfg.URL, err))
+		}
+	}
+}
+
+// deliver POSTs one event, retrying with exponential backoff on network
+// errors and 5xx/429 responses
+func (s *Sender) deliver(ep *endpoint, d delivery) error {
+	backoff := initialBackoff
+	var err error
+
+	for attempt := 0; attempt <= ep.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-s.stop:
+				return err
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+
+		var retry bool
+		retry, err = ep.post(d)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+func (ep *endpoint) post(d delivery) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, ep.cfg.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(d.kind))
+	if ep.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(ep.cfg.Secret, d.body))
+	}
+
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("server returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("server returned %s", resp.Status)
+	}
+}
+
+func (s *Sender) fail(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+// Sign returns the signature header value for body, so receivers can verify
+// requests with the shared secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+)
+
+// received is a request as the test server saw it
+type received struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+// recorder is a test endpoint answering with the given status codes in
+// turn, then 200
+type recorder struct {
+	mu       sync.Mutex
+	requests []received
+	statuses []int
+	got      chan struct{}
+}
+
+func newRecorder(statuses ...int) *recorder {
+	return &recorder{statuses: statuses, got: make(chan struct{}, 100)}
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, received{
+		event:     r.Header.Get(EventHeader),
+		signature: r.Header.Get(SignatureHeader),
+		body:      body,
+	})
+	status := http.StatusOK
+	if len(rec.statuses) > 0 {
+		status, rec.statuses = rec.statuses[0], rec.statuses[1:]
+	}
+	rec.mu.Unlock()
+	w.WriteHeader(status)
+	rec.got <- struct{}{}
+}
+
+// wait blocks until n requests arrived
+func (rec *recorder) wait(t *testing.T, n int) []received {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-rec.got:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d requests, want %d", i, n)
+		}
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]received(nil), rec.requests...)
+}
+
+func retries(n int) *int {
+	return &n
+}
+
+func TestSignature(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := New([]config.Webhook{{URL: srv.URL, Secret: "s3cret"}})
+	defer s.Close()
+	s.Send(events.Event{Kind: events.KindStart, Mode: "tabata"})
+
+	got := rec.wait(t, 1)[0]
+	if got.event != "start" {
+		t.Errorf("event header %q, want start", got.event)
+	}
+	if want := Sign("s3cret", got.body); got.signature != want {
+		t.Errorf("signature %q, want %q", got.signature, want)
+	}
+	if !strings.HasPrefix(got.signature, "sha256=") {
+		t.Errorf("signature %q lacks the sha256= prefix", got.signature)
+	}
+	if !strings.Contains(string(got.body), `"mode":"tabata"`) {
+		t.Errorf("body %s lacks the event", got.body)
+	}
+}
+
+func TestNoSignatureWithoutSecret(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := New([]config.Webhook{{URL: srv.URL}})
+	defer s.Close()
+	s.Send(events.Event{Kind: events.KindStart})
+
+	if got := rec.wait(t, 1)[0]; got.signature != "" {
+		t.Errorf("unsigned webhook sent signature %q", got.signature)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	rec := newRecorder(http.StatusInternalServerError, http.StatusServiceUnavailable)
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	var failures atomic.Int32
+	s := New([]config.Webhook{{URL: srv.URL, Retries: retries(2)}})
+	s.OnError = func(error) { failures.Add(1) }
+	defer s.Close()
+
+	start := time.Now()
+	s.Send(events.Event{Kind: events.KindFinish})
+	rec.wait(t, 3)
+
+	// Backs off 500ms, then 1s
+	if waited := time.Since(start); waited < initialBackoff*3 {
+		t.Errorf("retried after %s, want backoff of at least %s", waited, initialBackoff*3)
+	}
+	if n := failures.Load(); n != 0 {
+		t.Errorf("%d failures reported for a delivery that succeeded", n)
+	}
+}
+
+func TestRetriesOff(t *testing.T) {
+	rec := newRecorder(http.StatusInternalServerError, http.StatusInternalServerError)
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	failed := make(chan error, 1)
+	s := New([]config.Webhook{{URL: srv.URL, Retries: retries(0)}})
+	s.OnError = func(err error) { failed <- err }
+	defer s.Close()
+	s.Send(events.Event{Kind: events.KindStart})
+
+	select {
+	case <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no failure reported")
+	}
+	if got := rec.wait(t, 1); len(got) != 1 {
+		t.Errorf("%d attempts with retries off, want 1", len(got))
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	rec := newRecorder(http.StatusBadRequest)
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	failed := make(chan error, 1)
+	s := New([]config.Webhook{{URL: srv.URL}})
+	s.OnError = func(err error) { failed <- err }
+	defer s.Close()
+	s.Send(events.Event{Kind: events.KindStart})
+
+	select {
+	case err := <-failed:
+		if !strings.Contains(err.Error(), "400") {
+			t.Errorf("error %q does not mention the status", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no failure reported")
+	}
+	if got := rec.wait(t, 1); len(got) != 1 {
+		t.Errorf("%d attempts for a 400, want 1", len(got))
+	}
+}
+
+func TestOnFilter(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := New([]config.Webhook{{URL: srv.URL, On: []string{"finish"}}})
+	for _, kind := range []events.Kind{events.KindStart, events.KindTick, events.KindFinish} {
+		s.Send(events.Event{Kind: kind})
+	}
+	s.Close()
+
+	got := rec.wait(t, 1)
+	if len(got) != 1 || got[0].event != "finish" {
+		t.Errorf("got %v, want only the finish event", got)
+	}
+}
+
+func TestSendAfterClose(t *testing.T) {
+	rec := newRecorder()
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := New([]config.Webhook{{URL: srv.URL}})
+	s.Send(events.Event{Kind: events.KindStart})
+	s.Close()
+	s.Send(events.Event{Kind: events.KindFinish})
+	s.Close()
+
+	if got := rec.wait(t, 1); len(got) != 1 || got[0].event != "start" {
+		t.Errorf("got %v, want only the event sent before Close", got)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var dropped atomic.Int32
+	s := New([]config.Webhook{{URL: srv.URL}})
+	s.OnError = func(err error) {
+		if strings.Contains(err.Error(), "queue full") {
+			dropped.Add(1)
+		}
+	}
+	defer s.Close()
+
+	// One request blocks the worker; the rest fill the queue
+	sent := queueSize + 10
+	for i := 0; i < sent; i++ {
+		s.Send(events.Event{Kind: events.KindRound})
+	}
+	if n := dropped.Load(); n < 9 || n > 10 {
+		t.Errorf("dropped %d events, want 9 or 10", n)
+	}
+}
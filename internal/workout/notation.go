@@ -0,0 +1,213 @@
+package workout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/timer"
+)
+
+// Parse reads a workout written in short notation, e.g. "EMOM 12",
+// "EMOM 10 x 2m", "AMRAP 20", "For Time 10", "Tabata 20/10 x8",
+// "Custom 8 x 40s/20s", "Custom 4 x 30s/15s ladder +10s", "Tabata 8 x
+// 20s/10s 4 sets 1m" (1 minute between sets), "Death by 3+2" (3 reps in
+// the first minute, 2 more each minute) or "Chaos 20 15s-45s/10s-30s seed
+// 4821" (random intervals within the ranges for 20 minutes). A bare number
+// is minutes for AMRAP, chaos and the For Time cap and rounds otherwise;
+// bare numbers in a work/rest pair are seconds. The summaries printed by
+// the timer parse back to the same workout.
+func Parse(notation string) (Workout, error) {
+	fields := strings.Fields(strings.ToLower(notation))
+	if len(fields) == 0 {
+		return Workout{}, fmt.Errorf("empty workout notation")
+	}
+	if len(fields) > 1 && fields[0] == "for" && fields[1] == "time" {
+		fields = append([]string{"fortime"}, fields[2:]...)
+	}
+	if len(fields) > 1 && fields[0] == "death" && fields[1] == "by" {
+		fields = append([]string{"deathby"}, fields[2:]...)
+	}
+
+	mode, ok := timer.ParseMode(fields[0])
+	if !ok || mode == timer.ModeClock {
+		return Workout{}, fmt.Errorf("unknown workout %q", notation)
+	}
+	w := Workout{Mode: fields[0]}
+
+	roundsNext := false
+	for i := 1; i < len(fields); i++ {
+		f := fields[i]
+		if f == "x" {
+			roundsNext = true
+			continue
+		}
+		if n, ok := strings.CutPrefix(f, "x"); ok && n != "" {
+			f, roundsNext = n, true
+		}
+
+		// "4 sets", optionally followed by the rest between them
+		if i+1 < len(fields) && fields[i+1] == "sets" {
+			n, err := strconv.Atoi(f)
+			if err != nil || n < 1 {
+				return Workout{}, fmt.Errorf("%q: invalid sets %q", notation, f)
+			}
+			w.Sets = n
+			i++
+			if i+1 < len(fields) {
+				if d, err := parseNotationDuration(fields[i+1], 0); err == nil {
+					w.SetRest = config.Duration(d)
+					i++
+				}
+			}
+			continue
+		}
+
+		if f == "seed" && i+1 < len(fields) {
+			n, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil || n < 1 || mode != timer.ModeChaos {
+				return Workout{}, fmt.Errorf("%q: invalid seed %q", notation, fields[i+1])
+			}
+			w.Seed = n
+			i++
+			continue
+		}
+
+		if start, step, ok := strings.Cut(f, "+"); ok && mode == timer.ModeDeathBy {
+			var err error
+			if w.StartReps, err = strconv.Atoi(start); err != nil || w.StartReps < 1 {
+				return Workout{}, fmt.Errorf("%q: invalid reps %q", notation, f)
+			}
+			if w.RepStep, err = strconv.Atoi(step); err != nil || w.RepStep < 1 {
+				return Workout{}, fmt.Errorf("%q: invalid reps %q", notation, f)
+			}
+			continue
+		}
+		if p, ok := timer.ParseProgression(f); ok && p != timer.Fixed {
+			w.Progression = string(p)
+			continue
+		}
+		if w.Progression != "" && (f[0] == '+' || f[0] == '-') {
+			work, rest, _ := strings.Cut(f, "/")
+			ws, err := parseStep(work)
+			if err != nil {
+				return Workout{}, fmt.Errorf("%q: %w", notation, err)
+			}
+			w.WorkStep = config.Duration(ws)
+			if rest != "" {
+				rs, err := parseStep(rest)
+				if err != nil {
+					return Workout{}, fmt.Errorf("%q: %w", notation, err)
+				}
+				w.RestStep = config.Duration(rs)
+			}
+			continue
+		}
+
+		if work, rest, ok := strings.Cut(f, "/"); ok {
+			wd, wmax, err := parseRange(work, mode == timer.ModeChaos)
+			if err != nil {
+				return Workout{}, fmt.Errorf("%q: %w", notation, err)
+			}
+			rd, rmax, err := parseRange(rest, mode == timer.ModeChaos)
+			if err != nil {
+				return Workout{}, fmt.Errorf("%q: %w", notation, err)
+			}
+			w.Work, w.Rest = config.Duration(wd), config.Duration(rd)
+			w.WorkMax, w.RestMax = config.Duration(wmax), config.Duration(rmax)
+			continue
+		}
+
+		if n, err := strconv.Atoi(f); err == nil && n > 0 {
+			if (mode == timer.ModeAMRAP || mode == timer.ModeForTime || mode == timer.ModeChaos) && !roundsNext {
+				w.Duration = config.Duration(time.Duration(n) * time.Minute)
+			} else {
+				w.Rounds = n
+			}
+			roundsNext = false
+			continue
+		}
+
+		d, err := parseNotationDuration(f, 0)
+		if err != nil {
+			return Workout{}, fmt.Errorf("%q: unexpected %q", notation, f)
+		}
+		switch mode {
+		case timer.ModeEMOM:
+			w.Every = config.Duration(d)
+		case timer.ModeAMRAP, timer.ModeForTime, timer.ModeChaos:
+			w.Duration = config.Duration(d)
+		default:
+			w.Work = config.Duration(d)
+		}
+	}
+
+	if mode == timer.ModeStopwatch && (w.Rounds > 0 || w.Work > 0) {
+		return Workout{}, fmt.Errorf("%q: stopwatch takes no settings", notation)
+	}
+	return w, nil
+}
+
+// parseRange reads a work or rest length, or when ranges are allowed a
+// range such as "15s-45s"; bare numbers are seconds. The longest is zero
+// for a single length.
+func parseRange(s string, ranges bool) (shortest, longest time.Duration, err error) {
+	lo, hi, ok := strings.Cut(s, "-")
+	if !ok || !ranges {
+		shortest, err = parseNotationDuration(s, time.Second)
+		return shortest, 0, err
+	}
+	if shortest, err = parseNotationDuration(lo, time.Second); err != nil {
+		return 0, 0, err
+	}
+	if longest, err = parseNotationDuration(hi, time.Second); err != nil {
+		return 0, 0, err
+	}
+	if longest < shortest {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return shortest, longest, nil
+}
+
+// parseStep reads a signed step such as "+10s" or "-5"; bare numbers are
+// seconds
+func parseStep(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	default:
+		return 0, fmt.Errorf("invalid step %q", s)
+	}
+	if s == "0s" || s == "0" {
+		return 0, nil
+	}
+	d, err := parseNotationDuration(s, time.Second)
+	if err != nil {
+		return 0, err
+	}
+	return sign * d, nil
+}
+
+// parseNotationDuration reads "2m", "90s" or "1m30s", or a bare number in
+// unit if unit is not zero
+func parseNotationDuration(s string, unit time.Duration) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil && unit != 0 && n > 0 {
+		return time.Duration(n) * unit, nil
+	}
+	for _, long := range []string{"mins", "min"} {
+		if n, ok := strings.CutSuffix(s, long); ok {
+			s = n + "m"
+			break
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,210 @@
+package workout
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gymtimer/internal/config"
+	"gymtimer/internal/timer"
+)
+
+// Workout is a saved timer configuration, e.g.
+//
+//	{"name": "Tabata squats", "mode": "tabata", "work": "20s", "rest": "10s", "rounds": 8}
+//
+// Unset fields keep the defaults of the mode.
+type Workout struct {
+	Name     string          `json:"name,omitempty"`
+	Mode     string          `json:"mode"`
+	Work     config.Duration `json:"work,omitempty"`
+	Every    config.Duration `json:"every,omitempty"` // EMOM interval length
+	Rest     config.Duration `json:"rest,omitempty"`
+	Rounds   int             `json:"rounds,omitempty"`   // Per set, for workouts in sets
+	Duration config.Duration `json:"duration,omitempty"` // AMRAP length or For Time cap
+
+	// Tabata and custom: repeat the rounds this many times with set_rest
+	// between
+	Sets    int             `json:"sets,omitempty"`
+	SetRest config.Duration `json:"set_rest,omitempty"`
+
+	// Custom intervals that change each round: "ladder" adds the steps
+	// every round, "pyramid" climbs to the middle round and back down
+	Progression string          `json:"progression,omitempty"`
+	WorkStep    config.Duration `json:"work_step,omitempty"`
+	RestStep    config.Duration `json:"rest_step,omitempty"`
+
+	// Partners taking turns, one round each (EMOM and custom)
+	Athletes []string `json:"athletes,omitempty"`
+
+	// What the athletes do each round (EMOM, Tabata and custom); either a
+	// label or {"label": ..., "note": ...}
+	Movements []timer.Movement `json:"movements,omitempty"`
+	Rotate    bool             `json:"rotate,omitempty"` // EMOM rounds cycle through the movements
+
+	// Chaos: work and rest are drawn between work and work_max and between
+	// rest and rest_max for the duration; the same seed repeats a session
+	WorkMax config.Duration `json:"work_max,omitempty"`
+	RestMax config.Duration `json:"rest_max,omitempty"`
+	Seed    int64           `json:"seed,omitempty"`
+
+	// Death by: reps in the first minute and how many each minute adds
+	StartReps int `json:"start_reps,omitempty"`
+	RepStep   int `json:"rep_step,omitempty"`
+
+	// Circuits: stations visited in turn, the time to move between them
+	// and how many times round
+	Stations []string        `json:"stations,omitempty"`
+	Move     config.Duration `json:"move,omitempty"`
+	Laps     int             `json:"laps,omitempty"`
+
+	// Benchmarks: what the athletes do and how the result is scored
+	Description string `json:"description,omitempty"`
+	Scoring     string `json:"scoring,omitempty"` // time, reps, rounds+reps or load
+}
+
+// Load reads a workout file
+func Load(path string) (Workout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Workout{}, err
+	}
+
+	var w Workout
+	if err := json.Unmarshal(data, &w); err != nil {
+		return Workout{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Timer builds a timer configured for the workout
+func (w Workout) Timer() (*timer.Timer, error) {
+	mode, ok := timer.ParseMode(w.Mode)
+	if !ok {
+		return nil, fmt.Errorf("unknown mode %q", w.Mode)
+	}
+
+	t := timer.New()
+	t.SetMode(mode)
+	t.Name = w.Name
+
+	if w.Work > 0 {
+		t.WorkDuration = time.Duration(w.Work)
+	}
+	if w.Every > 0 {
+		t.WorkDuration = time.Duration(w.Every)
+	}
+	if w.Rest > 0 {
+		t.RestDuration = time.Duration(w.Rest)
+	}
+	if w.Rounds > 0 {
+		t.TotalRounds = w.Rounds
+	}
+	if w.Duration > 0 {
+		t.Duration = time.Duration(w.Duration)
+	}
+	if w.Sets > 0 || w.SetRest > 0 {
+		if mode != timer.ModeTabata && mode != timer.ModeCustom {
+			return nil, fmt.Errorf("sets are only for tabata and custom workouts")
+		}
+		if w.SetRest > 0 {
+			t.SetRest = time.Duration(w.SetRest)
+		}
+		t.SetSets(max(1, w.Sets))
+	}
+	if w.Progression != "" || w.WorkStep != 0 || w.RestStep != 0 {
+		p, ok := timer.ParseProgression(w.Progression)
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("unknown progression %q", w.Progression)
+		case mode != timer.ModeCustom:
+			return nil, fmt.Errorf("progressions are only for custom workouts")
+		case p != timer.Fixed && w.WorkStep == 0 && w.RestStep == 0:
+			return nil, fmt.Errorf("a %s needs a work_step or rest_step", p)
+		case p == timer.Fixed:
+			return nil, fmt.Errorf("steps need a progression, ladder or pyramid")
+		}
+		t.Progression = p
+		t.WorkStep = time.Duration(w.WorkStep)
+		t.RestStep = time.Duration(w.RestStep)
+	}
+	if w.StartReps != 0 || w.RepStep != 0 {
+		if mode != timer.ModeDeathBy {
+			return nil, fmt.Errorf("start_reps and rep_step are only for death by workouts")
+		}
+		if w.StartReps < 0 || w.RepStep < 0 {
+			return nil, fmt.Errorf("reps must be positive")
+		}
+		if w.StartReps > 0 {
+			t.StartReps = w.StartReps
+		}
+		if w.RepStep > 0 {
+			t.RepStep = w.RepStep
+		}
+	}
+	if w.WorkMax > 0 || w.RestMax > 0 || w.Seed != 0 {
+		if mode != timer.ModeChaos {
+			return nil, fmt.Errorf("work_max, rest_max and seed are only for chaos workouts")
+		}
+		if w.Seed < 0 {
+			return nil, fmt.Errorf("seed must be positive")
+		}
+		if w.Seed > 0 {
+			t.Seed = w.Seed
+		}
+	}
+	if mode == timer.ModeChaos {
+		if w.Rounds > 0 {
+			return nil, fmt.Errorf("chaos workouts run for a duration, not rounds")
+		}
+		// Without a longest interval the range is the one length given
+		switch {
+		case w.WorkMax > 0:
+			t.WorkMax = time.Duration(w.WorkMax)
+		case w.Work > 0:
+			t.WorkMax = t.WorkDuration
+		}
+		switch {
+		case w.RestMax > 0:
+			t.RestMax = time.Duration(w.RestMax)
+		case w.Rest > 0:
+			t.RestMax = t.RestDuration
+		}
+		if t.WorkMax < t.WorkDuration || t.RestMax < t.RestDuration {
+			return nil, fmt.Errorf("work_max and rest_max must not be shorter than work and rest")
+		}
+		t.Draw()
+	}
+	if mode == timer.ModeCircuit {
+		if w.Rounds > 0 {
+			return nil, fmt.Errorf("circuits count laps, not rounds")
+		}
+		if len(w.Stations) > 0 {
+			t.Stations = w.Stations
+		}
+		if w.Move > 0 {
+			t.RestDuration = time.Duration(w.Move)
+		}
+		t.SetLaps(max(1, w.Laps))
+	}
+	if len(w.Movements) > 0 {
+		if mode != timer.ModeEMOM && mode != timer.ModeTabata && mode != timer.ModeCustom {
+			return nil, fmt.Errorf("movements are only for emom, tabata and custom workouts")
+		}
+		t.Movements = w.Movements
+	}
+	if w.Rotate {
+		if mode != timer.ModeEMOM || len(w.Movements) < 2 {
+			return nil, fmt.Errorf("rotate needs an emom workout with at least 2 movements")
+		}
+		t.Rotation = len(w.Movements)
+	}
+	if len(w.Athletes) > 0 {
+		if mode != timer.ModeEMOM && mode != timer.ModeCustom {
+			return nil, fmt.Errorf("athletes take turns only in emom and custom workouts")
+		}
+		t.Athletes = w.Athletes
+	}
+	return t, nil
+}
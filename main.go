@@ -4,24 +4,102 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gymtimer/internal/audio"
+	"gymtimer/internal/config"
+	"gymtimer/internal/events"
+	"gymtimer/internal/history"
+	"gymtimer/internal/hooks"
+	"gymtimer/internal/leaderboard"
+	"gymtimer/internal/metrics"
+	"gymtimer/internal/mqtt"
+	"gymtimer/internal/osc"
+	"gymtimer/internal/schedule"
+	"gymtimer/internal/session"
+	"gymtimer/internal/timer"
 	"gymtimer/internal/ui"
+	"gymtimer/internal/webhook"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	// Determine assets path
-	execPath, err := os.Executable()
-	if err != nil {
-		execPath = "."
+	os.Exit(runCLI(os.Args[1:]))
+}
+
+// runTUI starts the interactive timer. When t is set it starts running
+// immediately, or at the given time if at is not zero. Otherwise the clock
+// arms each class of plan in turn, if any. A benchmark in o scores t's
+// result against earlier ones, and a heat times several athletes on t.
+func runTUI(t *timer.Timer, at time.Time, o *options, plan *schedule.Schedule, cfg *config.Config, audioPlayer *audio.Player) error {
+	dispatcher := events.NewDispatcher()
+
+	// Create the app model
+	model := ui.New(audioPlayer, dispatcher).WithExport(history.DefaultPath(), cfg.ExportDir, cfg.ExportFormat)
+	if t != nil && !at.IsZero() {
+		model = model.WithSchedule(t, at).WithSession(session.DefaultPath(), nil)
+	} else if t != nil {
+		model = model.WithWorkout(t).WithSession(session.DefaultPath(), nil)
+	} else if plan != nil {
+		model = model.WithPlan(plan).WithSession(session.DefaultPath(), nil)
+	} else {
+		// Offer to resume a session that was cut short
+		pending, err := session.Load(session.DefaultPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read saved session: %v\n", err)
+		}
+		model = model.WithSession(session.DefaultPath(), pending)
+	}
+	if t != nil && o.benchmark != nil {
+		model = model.WithBenchmark(*o.benchmark)
+	}
+	if t != nil && o.heat != nil {
+		model = model.WithHeat(o.heat)
 	}
-	assetsDir := filepath.Join(filepath.Dir(execPath), "assets")
 
-	// Also check current directory
-	if _, err := os.Stat(assetsDir); os.IsNotExist(err) {
-		assetsDir = "assets"
+	boardPath := o.leaderboard
+	if boardPath == "" {
+		boardPath = cfg.Leaderboard
+	}
+	if boardPath == "" {
+		boardPath = leaderboard.DefaultPath()
+	}
+	model = model.WithLeaderboard(boardPath, o.showBoard)
+
+	// Create the Bubbletea program
+	p := tea.NewProgram(model, tea.WithAltScreen())
+
+	// Remote control needs the program to deliver commands to
+	remote := func(action, arg string) {
+		p.Send(ui.CommandMsg{Action: action, Arg: arg})
+	}
+	// The recorder runs inside Update, which must not wait on the program
+	recorded := func(s history.Session) {
+		go p.Send(ui.RecordedMsg{Session: s})
+	}
+	cleanup := setupIntegrations(cfg, dispatcher, audioPlayer, remote, recorded)
+	defer cleanup()
+
+	_, err := p.Run()
+	return err
+}
+
+// newAudioPlayer makes sure the sound files exist and creates the player.
+// An empty assetsDir looks next to the executable, then in ./assets.
+func newAudioPlayer(assetsDir string) *audio.Player {
+	if assetsDir == "" {
+		// Determine assets path
+		execPath, err := os.Executable()
+		if err != nil {
+			execPath = "."
+		}
+		assetsDir = filepath.Join(filepath.Dir(execPath), "assets")
+
+		// Also check current directory
+		if _, err := os.Stat(assetsDir); os.IsNotExist(err) {
+			assetsDir = "assets"
+		}
 	}
 
 	beepPath := filepath.Join(assetsDir, "beep.wav")
@@ -46,17 +124,77 @@ func main() {
 		}
 	}
 
-	// Create audio player
-	audioPlayer := audio.New(beepPath, chimePath)
+	return audio.New(beepPath, chimePath)
+}
 
-	// Create the app model
-	model := ui.New(audioPlayer)
+// setupIntegrations registers the session history recorder and the
+// configured event listeners (hooks, webhooks, MQTT, OSC, metrics).
+// remote receives incoming commands and recorded each saved session; either
+// may be nil. The returned function flushes and closes them.
+func setupIntegrations(cfg *config.Config, dispatcher *events.Dispatcher, audioPlayer *audio.Player, remote func(action, arg string), recorded func(history.Session)) func() {
+	var closers []func()
+	cleanup := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
 
-	// Create and run the Bubbletea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	// Integration failures are only visible through metrics, since the TUI
+	// owns the terminal
+	reportError := func(string) func(error) { return nil }
+	if cfg.Metrics != nil && cfg.Metrics.Listen != "" {
+		registry := metrics.New()
+		dispatcher.Add(registry)
+		audioPlayer.SetErrorHandler(func(error) { registry.AudioFailure() })
+		reportError = func(name string) func(error) {
+			return func(error) { registry.IntegrationError(name) }
+		}
+		if err := registry.Listen(cfg.Metrics.Listen); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: metrics endpoint disabled: %v\n", err)
+		}
+	}
 
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
-		os.Exit(1)
+	recorder := history.NewRecorder(history.DefaultPath())
+	recorder.OnError = reportError("history")
+	recorder.OnRecord = recorded
+	dispatcher.Add(recorder)
+
+	if len(cfg.Hooks) > 0 {
+		runner := hooks.New(cfg.Hooks)
+		runner.OnError = reportError("hooks")
+		dispatcher.Add(runner)
+	}
+	if len(cfg.Webhooks) > 0 {
+		sender := webhook.New(cfg.Webhooks)
+		sender.OnError = reportError("webhook")
+		closers = append(closers, sender.Close)
+		dispatcher.Add(sender)
+	}
+
+	if cfg.MQTT != nil && cfg.MQTT.Broker != "" {
+		client, err := mqtt.Connect(*cfg.MQTT)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: MQTT disabled: %v\n", err)
+		} else if bridge, err := mqtt.New(*cfg.MQTT, client, remote); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: MQTT disabled: %v\n", err)
+			client.Disconnect()
+		} else {
+			bridge.OnError = reportError("mqtt")
+			closers = append(closers, bridge.Close)
+			dispatcher.Add(bridge)
+		}
 	}
+
+	if cfg.OSC != nil {
+		bridge, err := osc.New(*cfg.OSC, remote)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: OSC disabled: %v\n", err)
+		} else {
+			bridge.OnError = reportError("osc")
+			closers = append(closers, bridge.Close)
+			dispatcher.Add(bridge)
+		}
+	}
+
+	return cleanup
 }